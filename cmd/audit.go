@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/audit"
+	"github.com/zalando/rds-health/internal/lifecycle"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	auditOnly      []string
+	auditLifecycle string
+	auditIgnore    []string
+	auditRules     []audit.Rule
+	auditFilter    types.Filter
+	auditStatus    types.StatusRegion
+)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	withFilterFlags(auditCmd)
+	auditCmd.Flags().StringSliceVar(&auditOnly, "rules", nil, "comma separated list of rules to audit ("+audit.IDs()+")")
+	auditCmd.Flags().StringSliceVar(&auditIgnore, "ignore", nil, "comma separated list of rules to ignore")
+	withOutputFlag(auditCmd, auditRegionOutput(nil).Formats(), auditNodeOutput.Formats())
+	withFailOnFlags(auditCmd)
+	auditCmd.Flags().StringVar(&auditLifecycle, "lifecycle", "", "file with engine versions lifecycle, overrides embedded dataset")
+	auditCmd.InheritedFlags().SetAnnotation("interval", cobra.BashCompOneRequiredFlag, []string{"false"})
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "audit configuration of database instances against best-practices",
+	Example: `
+rds-health audit
+rds-health audit -n myrds
+rds-health audit -o verbose
+rds-health audit -o sarif > rds-audit.sarif
+rds-health audit --fail-on-rule A1,A8
+rds-health audit --ignore A4,A7 --tag team=payments
+	`,
+	SilenceUsage: true,
+	PreRunE:      auditOpts,
+	RunE:         WithService(auditConfig),
+	PostRunE:     auditPost,
+}
+
+func auditOpts(cmd *cobra.Command, args []string) (err error) {
+	auditRules, err = audit.Select(auditOnly, auditIgnore)
+	if err != nil {
+		return err
+	}
+
+	auditFilter, err = parseFilter()
+	if err != nil {
+		return err
+	}
+
+	if err := parseFailOn(auditRulesOf); err != nil {
+		return err
+	}
+
+	if auditLifecycle != "" {
+		lifecycle.Default, err = lifecycle.Load(auditLifecycle)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func auditPost(cmd *cobra.Command, args []string) error {
+	if (rootDatabase == "") && outFormat != "verbose" && outHuman() {
+		stderr("\n(use \"rds-health audit -o verbose\" to see details)\n")
+	}
+
+	if (rootDatabase == "") && outFormat == "verbose" && outHuman() {
+		stderr("\n(use \"rds-health audit -n NAME\" for the audit of the instance)\n")
+	}
+
+	if rootDatabase != "" && outFormat != "verbose" && outHuman() {
+		stderr("\n(use \"rds-health audit -o verbose -n " + rootDatabase + "\" to see full report)\n")
+	}
+
+	return exitStatus(auditStatus)
+}
+
+// matcher of audit rules deciding the exit code
+func auditRulesOf(ids []string) (func(types.Rule) bool, error) {
+	if _, err := audit.Select(ids, nil); err != nil {
+		return nil, err
+	}
+
+	return func(rule types.Rule) bool { return slices.Contains(ids, rule.ID) }, nil
+}
+
+func auditConfig(cmd *cobra.Command, args []string, api Service) error {
+	if rootDatabase == "" {
+		ids := make([]string, len(auditRules))
+		for i, rule := range auditRules {
+			ids[i] = rule.ID
+		}
+
+		out, err := lookupOutput(auditRegionOutput(ids))
+		if err != nil {
+			return err
+		}
+
+		status, err := api.AuditRegion(cmd.Context(), auditFilter, auditRules)
+		if err != nil {
+			return err
+		}
+
+		auditStatus = *status
+		return stdout(out.Show(*status))
+	}
+
+	out, err := lookupOutput(auditNodeOutput)
+	if err != nil {
+		return err
+	}
+
+	status, err := api.AuditNode(cmd.Context(), rootDatabase, auditRules)
+	if err != nil {
+		return err
+	}
+
+	auditStatus = types.StatusRegion{Status: status.Status, Nodes: []types.StatusNode{*status}}
+	return stdout(out.Show(*status))
+}
@@ -9,12 +9,15 @@
 package cmd
 
 import (
-	"os"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/baseline"
+	"github.com/zalando/rds-health/internal/rules"
 	"github.com/zalando/rds-health/internal/show"
-	"github.com/zalando/rds-health/internal/show/minimal"
+	"github.com/zalando/rds-health/internal/show/format"
 	"github.com/zalando/rds-health/internal/show/verbose"
 	"github.com/zalando/rds-health/internal/types"
 )
@@ -22,11 +25,31 @@ import (
 var (
 	// checkIgnore   string
 	checkDuration time.Duration
-	checkStatus   types.StatusCode
+	checkFilter   types.Filter
+	checkStatus   types.StatusRegion
+	checkSave     string
+	checkBaseline string
+	checkPrev     types.StatusRegion
 )
 
 func init() {
 	rootCmd.AddCommand(checkCmd)
+	withFilterFlags(checkCmd)
+	withGroupByFlag(checkCmd)
+	withNotifyFlags(checkCmd)
+	withOutputFlag(checkCmd, checkRegionOutput.Formats(), checkNodeOutput.Formats())
+	withChartFlag(checkCmd)
+	withFormatFlags(checkCmd)
+	withFailOnFlags(checkCmd)
+	withDiffFlags(checkCmd)
+	withHistoryFlag(checkCmd)
+	checkCmd.Flags().BoolVar(&historyOff, "no-history", false, "do not append health status to the history store")
+	checkCmd.Flags().DurationVar(&historyRetention, "history-retention", 90*24*time.Hour, "drop records of the history store older than the period, 0 keeps all")
+	checkCmd.Flags().StringVar(&checkSave, "save", "", "save health status to the file, the snapshot for diff and --baseline")
+	checkCmd.Flags().StringVar(&checkBaseline, "baseline", "", "report changes of health status since the snapshot file")
+	for _, flag := range []string{"chart", "format", "format-file", "group-by"} {
+		checkCmd.MarkFlagsMutuallyExclusive("baseline", flag)
+	}
 	// checkCmd.Flags().StringVar(&checkIgnore, "ignore", "", "comma separated list of rules to ignore")
 }
 
@@ -35,6 +58,20 @@ var checkCmd = &cobra.Command{
 	Short: "check health status of database instance using AWS Performance Insights service",
 	Example: `
 rds-health check -n myrds -t 7d
+rds-health check -t 7d --tag team=payments --engine postgres
+rds-health check -t 7d --group-by team
+rds-health check -n myrds -t 7d --chart D3
+rds-health check -n myrds -t 7d -o verbose
+rds-health check -t 7d -o compact
+rds-health check -t 7d -o yaml
+rds-health check -t 7d -o junit > rds-health.xml
+rds-health check -t 7d -o html > rds-health.html
+rds-health check -t 7d -o csv > rds-health.csv
+rds-health check -t 7d --format '{{range nodes .}}{{icon .Status}}{{.Node.Name}}{{range failing .Checks}} {{.Rule.ID}}{{end}}{{"\n"}}{{end}}'
+rds-health check -t 7d --slack https://hooks.slack.com/services/... --notify-on warn
+rds-health check -t 7d --fail-on fail --fail-on-rule D3,P2
+rds-health check -t 7d --save rds-health.json
+rds-health check -t 7d --baseline rds-health.json
 	`,
 	SilenceUsage: true,
 	PreRunE:      checkOpts,
@@ -48,65 +85,166 @@ func checkOpts(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	checkFilter, err = parseFilter()
+	if err != nil {
+		return err
+	}
+
+	if err := parseFormat(); err != nil {
+		return err
+	}
+
+	if err := parseFailOn(checkRules); err != nil {
+		return err
+	}
+
+	if historyRetention < 0 {
+		return fmt.Errorf("history retention %s must not be negative", historyRetention)
+	}
+
+	// history is best effort, it is not kept if the store is not resolved
+	if !historyOff {
+		if err := parseHistory(); err != nil {
+			stderr(fmt.Sprintf("history is not kept: %s\n", err))
+			historyOff = true
+		}
+	}
+
+	if checkBaseline != "" {
+		checkPrev, err = readSnapshot(checkBaseline)
+		if err != nil {
+			return err
+		}
+	}
+
+	if outChart != "" && rootDatabase == "" {
+		return fmt.Errorf("chart requires database name, use -n NAME")
+	}
+
+	notifier, err = parseNotify()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func checkPost(cmd *cobra.Command, args []string) error {
-	if (rootDatabase == "") && !outVerbose && !outJsonify {
-		stderr("\n(use \"rds-health check -v\" to see details)\n")
+	if checkBaseline != "" {
+		return exitStatus(checkStatus)
 	}
 
-	if (rootDatabase == "") && outVerbose && !outJsonify {
-		stderr("\n(use \"rds-health check -n NAME\" for the status of the instance)\n")
+	if (rootDatabase == "") && outFormat != "verbose" && outHuman() {
+		stderr("\n(use \"rds-health check -o verbose\" to see details)\n")
 	}
 
-	if rootDatabase != "" && !outVerbose && !outJsonify {
-		stderr("\n(use \"rds-health check -v -n " + rootDatabase + "\" to see full report)\n")
+	if (rootDatabase == "") && outFormat == "verbose" && outHuman() {
+		stderr("\n(use \"rds-health check -n NAME\" for the status of the instance)\n")
 	}
 
-	if checkStatus > types.STATUS_CODE_SUCCESS {
-		os.Exit(128)
+	if rootDatabase != "" && outFormat != "verbose" && outHuman() {
+		stderr("\n(use \"rds-health check -o verbose -n " + rootDatabase + "\" to see full report)\n")
 	}
 
-	return nil
+	return exitStatus(checkStatus)
+}
+
+// matcher of health rules deciding the exit code, ids or profiles
+func checkRules(ids []string) (func(types.Rule) bool, error) {
+	return rules.Profile(strings.Join(ids, ","))
 }
 
 func check(cmd *cobra.Command, args []string, api Service) error {
 	if rootDatabase == "" {
-		var out show.Printer[types.StatusRegion] = minimal.ShowHealthRegion
+		var out show.Printer[types.StatusRegion]
+		var err error
 		switch {
-		case outVerbose:
-			out = minimal.ShowHealthRegionWithRules
-		case outSilent:
-			out = show.None[types.StatusRegion]()
-		case outJsonify:
-			out = show.JSON[types.StatusRegion]()
+		case outTmpl != nil:
+			out = format.Show[types.StatusRegion](outTmpl)
+		case checkBaseline != "":
+			out, err = checkChanges(func(r types.StatusRegion) []types.Change {
+				return baseline.Compare(checkPrev, r, diffOptions)
+			})
+		case groupBy != "":
+			out, err = checkGroups()
+		default:
+			out, err = lookupOutput(checkRegionOutput)
+		}
+		if err != nil {
+			return err
 		}
 
 		return checkRegion(cmd, args, api, out)
 	}
 
-	var out show.Printer[types.StatusNode] = minimal.ShowHealthNode
+	var out show.Printer[types.StatusNode]
+	var err error
 	switch {
-	case outVerbose:
-		out = verbose.ShowHealthNode
-	case outSilent:
-		out = show.None[types.StatusNode]()
-	case outJsonify:
-		out = show.JSON[types.StatusNode]()
+	case outChart != "":
+		out = verbose.ShowChartNode(outChart, termWidth())
+	case outTmpl != nil:
+		out = format.Show[types.StatusNode](outTmpl)
+	case checkBaseline != "":
+		out, err = checkChanges(func(n types.StatusNode) []types.Change {
+			return baseline.CompareNode(checkPrev, n, diffOptions)
+		})
+	default:
+		out, err = lookupOutput(checkNodeOutput)
+	}
+	if err != nil {
+		return err
 	}
 
 	return checkNode(cmd, args, api, out)
 }
 
+func checkGroups() (show.Printer[types.StatusRegion], error) {
+	out, err := lookupOutput(checkGroupsOutput)
+	if err != nil {
+		return nil, fmt.Errorf("group-by: %w", err)
+	}
+
+	return show.ContraMap[[]types.StatusRegionGroup, types.StatusRegion]{T: out}.FMap(
+		func(r types.StatusRegion) []types.StatusRegionGroup { return r.GroupBy(groupBy) },
+	), nil
+}
+
+// changes of health status since the baseline
+func checkChanges[T any](compare func(T) []types.Change) (show.Printer[T], error) {
+	out, err := lookupOutput(diffOutput)
+	if err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+
+	return show.ContraMap[[]types.Change, T]{T: out}.FMap(compare), nil
+}
+
 func checkRegion(cmd *cobra.Command, _ []string, api Service, show show.Printer[types.StatusRegion]) error {
-	status, err := api.CheckHealthRegion(cmd.Context(), checkDuration)
+	status, err := api.CheckHealthRegion(cmd.Context(), checkFilter, checkDuration)
 	if err != nil {
 		return err
 	}
 
-	checkStatus = status.Status
-	return stdout(show.Show(*status))
+	checkStatus = *status
+
+	if checkSave != "" {
+		if err := saveSnapshot(checkSave, *status); err != nil {
+			return err
+		}
+	}
+
+	if err := stdout(show.Show(*status)); err != nil {
+		return err
+	}
+
+	appendHistory(checkStatus)
+
+	// notification is best effort, the exit code reflects the health status
+	if err := notifyRegion(cmd.Context(), *status); err != nil {
+		stderr(fmt.Sprintf("notification failed: %s\n", err))
+	}
+
+	return nil
 }
 
 func checkNode(cmd *cobra.Command, _ []string, api Service, show show.Printer[types.StatusNode]) error {
@@ -115,6 +253,24 @@ func checkNode(cmd *cobra.Command, _ []string, api Service, show show.Printer[ty
 		return err
 	}
 
-	checkStatus = status.Status
-	return stdout(show.Show(*status))
+	checkStatus = types.StatusRegion{Status: status.Status, Nodes: []types.StatusNode{*status}}
+
+	if checkSave != "" {
+		if err := saveSnapshot(checkSave, *status); err != nil {
+			return err
+		}
+	}
+
+	if err := stdout(show.Show(*status)); err != nil {
+		return err
+	}
+
+	appendHistory(checkStatus)
+
+	// notification is best effort, the exit code reflects the health status
+	if err := notifyRegion(cmd.Context(), checkStatus); err != nil {
+		stderr(fmt.Sprintf("notification failed: %s\n", err))
+	}
+
+	return nil
 }
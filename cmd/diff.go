@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/baseline"
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	diffOptions = baseline.Default
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	withDiffFlags(diffCmd)
+	withOutputFlag(diffCmd, diffOutput.Formats())
+	diffCmd.InheritedFlags().SetAnnotation("database", cobra.BashCompOneRequiredFlag, []string{"false"})
+	diffCmd.InheritedFlags().SetAnnotation("interval", cobra.BashCompOneRequiredFlag, []string{"false"})
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD NEW",
+	Short: "compare health status snapshots",
+	Long:  "compare snapshots of health status (see check --save), reporting new failures, recoveries, significant changes of success rate or soft min/avg/max per node and rule as well as added and removed nodes",
+	Example: `
+rds-health check -t 7d --save before.json
+rds-health check -t 7d --save after.json
+rds-health diff before.json after.json
+rds-health diff before.json after.json --value-delta 0.5 -o json
+	`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         diff,
+	PostRun:      diffPost,
+}
+
+var diffChanges []types.Change
+
+func diff(cmd *cobra.Command, args []string) error {
+	out, err := lookupOutput(diffOutput)
+	if err != nil {
+		return err
+	}
+
+	old, err := readSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+
+	now, err := readSnapshot(args[1])
+	if err != nil {
+		return err
+	}
+
+	diffChanges = baseline.Compare(old, now, diffOptions)
+	return stdout(out.Show(diffChanges))
+}
+
+func diffPost(cmd *cobra.Command, args []string) {
+	if len(diffChanges) == 0 && outHuman() {
+		stderr("(no changes)\n")
+	}
+}
+
+// declares flags of significant changes
+func withDiffFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&diffOptions.SuccessRate, "rate-delta", baseline.Default.SuccessRate, "significant change of success rate, percentage points")
+	cmd.Flags().Float64Var(&diffOptions.Value, "value-delta", baseline.Default.Value, "significant relative change of soft min, avg or max (e.g. 0.2 is 20%)")
+}
+
+// reads snapshot of health status from the file
+func readSnapshot(path string) (types.StatusRegion, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return types.StatusRegion{}, err
+	}
+	defer f.Close()
+
+	return baseline.Read(f)
+}
+
+// writes snapshot of health status to the file
+func saveSnapshot[T any](path string, x T) error {
+	b, err := show.JSON[T]().Show(x)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// Exit codes of the utility
+const (
+	ExitPassed  = 0 // health status passed or it is below --fail-on
+	ExitError   = 1 // execution error (e.g. invalid flags, AWS APIs failure)
+	ExitWarned  = 2 // some rules are warned
+	ExitFailed  = 3 // some rules are failed
+	ExitUnknown = 4 // status is unknown, no data (with --fail-on warn only)
+)
+
+// ExitStatus is the error returned by commands if health status violates
+// the --fail-on policy. It carries the exit code of the process.
+type ExitStatus struct {
+	Code   int
+	Status types.StatusCode
+}
+
+func (e *ExitStatus) Error() string {
+	return fmt.Sprintf("health status is %s", strings.ToLower(e.Status.String()))
+}
+
+// ExitCode of the process for the error returned by the command
+func ExitCode(err error) int {
+	var status *ExitStatus
+
+	switch {
+	case err == nil:
+		return ExitPassed
+	case errors.As(err, &status):
+		return status.Code
+	default:
+		return ExitError
+	}
+}
+
+var (
+	failOn      string
+	failOnRules []string
+	failOnMatch func(types.Rule) bool
+)
+
+// declares flags of the exit code policy
+func withFailOnFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&failOn, "fail-on", "warn", "exit with non-zero code if status is at least warn (including unknown), fail or never")
+	cmd.Flags().StringSliceVar(&failOnRules, "fail-on-rule", nil, "comma separated list of rules deciding the exit code (default all rules)")
+}
+
+// decodes flags of the exit code policy, the command builds the matcher of
+// rules, validating their ids
+func parseFailOn(matcher func(ids []string) (func(types.Rule) bool, error)) (err error) {
+	switch failOn {
+	case "warn", "fail", "never":
+	default:
+		return fmt.Errorf("fail-on %s is not supported, use warn, fail or never", failOn)
+	}
+
+	failOnMatch = nil
+	if len(failOnRules) != 0 {
+		failOnMatch, err = matcher(failOnRules)
+	}
+
+	return err
+}
+
+// decides the exit of command from the health status of the region
+func exitStatus(region types.StatusRegion) error {
+	if failOn == "never" {
+		return nil
+	}
+
+	code := region.Status
+	if failOnMatch != nil {
+		code = region.WithRules(failOnMatch).Status
+	}
+
+	// absence of data is not a failure, it is reported as strict as warnings
+	switch {
+	case code == types.STATUS_CODE_UNKNOWN && failOn == "warn":
+		return &ExitStatus{Code: ExitUnknown, Status: code}
+	case code == types.STATUS_CODE_FAILURE:
+		return &ExitStatus{Code: ExitFailed, Status: code}
+	case code == types.STATUS_CODE_WARNING && failOn == "warn":
+		return &ExitStatus{Code: ExitWarned, Status: code}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/export"
+	"github.com/zalando/rds-health/internal/rules"
+)
+
+var (
+	exportDuration    time.Duration
+	exportMetrics     []string
+	exportAggregators []string
+	exportFile        string
+	exportAs          string
+	exportEncoder     export.Encoder
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringSliceVarP(&exportMetrics, "metric", "m", nil, "Performance Insights metric (e.g. os.cpuUtilization.total), the aggregator suffix is optional")
+	exportCmd.Flags().StringSliceVarP(&exportAggregators, "aggregator", "a", []string{string(rules.STATS_AVG)}, "aggregators avg, min, max, sum, sample_count or all")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "output file (default stdout)")
+	exportCmd.Flags().StringVar(&exportAs, "as", "", "output format csv, jsonl or parquet (default from file extension or csv)")
+	exportCmd.MarkFlagRequired("metric")
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export raw time series of metrics",
+	Long:  "export raw time series of AWS Performance Insights metrics as CSV, JSON lines or Parquet for analysis with external tools",
+	Example: `
+rds-health export -n name-of-rds-instance -t 7d -m db.load -m os.cpuUtilization.total
+rds-health export -n name-of-rds-instance -t 7d -m db.load -a all -f load.parquet
+rds-health export -n name-of-rds-instance -t 1d -m db.load.max --as jsonl
+	`,
+	SilenceUsage: true,
+	PreRunE:      exportOpts,
+	RunE:         WithService(exportNode),
+}
+
+func exportOpts(cmd *cobra.Command, args []string) (err error) {
+	exportDuration, err = parseInterval()
+	if err != nil {
+		return err
+	}
+
+	if rootDatabase == "" {
+		return fmt.Errorf("undefined database name")
+	}
+
+	exportEncoder, err = export.Lookup(exportAs, exportFile)
+	if err != nil {
+		return err
+	}
+
+	if exportFile == "" && exportAs == "parquet" && isTerminal(os.Stdout) {
+		return fmt.Errorf("parquet is binary format, use --file to write it")
+	}
+
+	for _, agg := range exportAggregators {
+		if agg != "all" && !slices.Contains(rules.Aggregators, rules.Aggregator(agg)) {
+			return fmt.Errorf("aggregator %s is not supported", agg)
+		}
+	}
+
+	return nil
+}
+
+// metrics to fetch, aggregator is appended unless metric defines it
+func exportQuery() []string {
+	aggregators := rules.Aggregators
+	if !slices.Contains(exportAggregators, "all") {
+		aggregators = make([]rules.Aggregator, len(exportAggregators))
+		for i, agg := range exportAggregators {
+			aggregators[i] = rules.Aggregator(agg)
+		}
+	}
+
+	query := make([]string, 0)
+	for _, metric := range exportMetrics {
+		if at := strings.LastIndexByte(metric, '.'); at != -1 && slices.Contains(rules.Aggregators, rules.Aggregator(metric[at+1:])) {
+			query = append(query, metric)
+			continue
+		}
+
+		for _, agg := range aggregators {
+			query = append(query, string(rules.Metric(metric).ToAgg(agg)[0]))
+		}
+	}
+
+	return slices.Compact(query)
+}
+
+func exportNode(cmd *cobra.Command, args []string, api Service) error {
+	seq, err := api.ExportNode(cmd.Context(), rootDatabase, exportDuration, exportQuery())
+	if err != nil {
+		return err
+	}
+
+	if exportFile == "" {
+		if outSilent {
+			return exportEncoder(io.Discard, seq)
+		}
+		return exportEncoder(os.Stdout, seq)
+	}
+
+	f, err := os.Create(exportFile)
+	if err != nil {
+		return err
+	}
+
+	if err := exportEncoder(f, seq); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,80 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	filterTags     []string
+	filterAttrs    []string
+	filterEngines  []string
+	filterClasses  []string
+	filterClusters []string
+	filterNames    []string
+	filterPattern  string
+	groupBy        string
+)
+
+// declares flags to filter database instances
+func withFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&filterTags, "tag", nil, "filter instances by tag KEY=VALUE or KEY (repeatable)")
+	cmd.Flags().StringArrayVar(&filterAttrs, "where", nil, "filter instances by attribute KEY=VALUE (e.g. multi-az=false, repeatable)")
+	cmd.Flags().StringSliceVar(&filterEngines, "engine", nil, "filter instances by engine (e.g. postgres, aurora-postgresql)")
+	cmd.Flags().StringSliceVar(&filterClasses, "class", nil, "filter instances by instance class (e.g. db.r5.large)")
+	cmd.Flags().StringSliceVar(&filterClusters, "cluster", nil, "filter instances by cluster identifier")
+	cmd.Flags().StringSliceVar(&filterNames, "name", nil, "filter instances by name glob pattern (e.g. \"payment-*\")")
+	cmd.Flags().StringVar(&filterPattern, "name-regex", "", "filter instances by name regular expression")
+}
+
+// declares flag to group output by the attribute or tag
+func withGroupByFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "group output by the value of the attribute or tag (e.g. multi-az, team, tag:status)")
+}
+
+// decodes filter flags to types.Filter
+func parseFilter() (types.Filter, error) {
+	filter := types.Filter{
+		Engines:  filterEngines,
+		Classes:  filterClasses,
+		Clusters: filterClusters,
+	}
+
+	var err error
+
+	filter.Names, err = types.ParseNames(filterNames)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Tags, err = types.ParseTags(filterTags)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Attributes, err = types.ParseAttributes(filterAttrs)
+	if err != nil {
+		return filter, err
+	}
+
+	if filterPattern != "" {
+		re, err := regexp.Compile(filterPattern)
+		if err != nil {
+			return filter, fmt.Errorf("invalid name regex: %w", err)
+		}
+		filter.Pattern = re
+	}
+
+	return filter, nil
+}
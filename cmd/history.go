@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/history"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	historyFile      string
+	historyOff       bool
+	historyLast      int
+	historyAccount   string
+	historyCluster   bool
+	historyRetention time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	withHistoryFlag(historyCmd)
+	withOutputFlag(historyCmd, historyOutput.Formats())
+	historyCmd.Flags().IntVar(&historyLast, "last", 24, "show the last checks only, 0 shows all")
+	historyCmd.Flags().StringVar(&historyAccount, "account", "", "AWS account of the instance if the name is used by multiple accounts")
+	historyCmd.Flags().BoolVar(&historyCluster, "cluster", false, "show history of cluster rules of the cluster named by -n")
+	historyCmd.InheritedFlags().SetAnnotation("interval", cobra.BashCompOneRequiredFlag, []string{"false"})
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "show history of health status",
+	Long:  "show history of health status of database instance kept by the check command in the local store, including how long rules do not pass",
+	Example: `
+rds-health history -n myrds
+rds-health history -n myrds --last 0 -o json
+rds-health history -n mycluster --cluster
+	`,
+	SilenceUsage: true,
+	PreRunE:      historyOpts,
+	RunE:         historyNode,
+}
+
+func historyOpts(cmd *cobra.Command, args []string) (err error) {
+	if rootDatabase == "" {
+		return fmt.Errorf("undefined database name")
+	}
+
+	if historyLast < 0 {
+		return fmt.Errorf("last %d must not be negative", historyLast)
+	}
+
+	return parseHistory()
+}
+
+func historyNode(cmd *cobra.Command, args []string) error {
+	out, err := lookupOutput(historyOutput)
+	if err != nil {
+		return err
+	}
+
+	// records of other regions are skipped, the region is defined by the
+	// AWS config like for other commands
+	scope := history.Scope{Account: historyAccount}
+	if conf, err := config.LoadDefaultConfig(context.Background()); err == nil {
+		scope.Region = conf.Region
+	}
+
+	store := history.New(historyFile)
+	lookup := store.Node
+	if historyCluster {
+		lookup = store.Cluster
+	}
+
+	records, err := lookup(rootDatabase, scope)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return fmt.Errorf("no history of %s is found, it is kept by the check command", rootDatabase)
+	}
+
+	accounts := make([]string, 0)
+	for _, r := range records {
+		if r.Account != "" && !slices.Contains(accounts, r.Account) {
+			accounts = append(accounts, r.Account)
+		}
+	}
+
+	if len(accounts) > 1 {
+		return fmt.Errorf("history of %s is found in accounts %s, use --account", rootDatabase, strings.Join(accounts, ", "))
+	}
+
+	h := history.Of(rootDatabase, records)
+	if historyLast > 0 && len(h.Records) > historyLast {
+		h.Records = h.Records[len(h.Records)-historyLast:]
+	}
+
+	return stdout(out.Show(h))
+}
+
+// declares flag of the history store
+func withHistoryFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&historyFile, "history", "", "file of the history store (default is history.jsonl in the user config directory)")
+}
+
+// decodes flag of the history store
+func parseHistory() (err error) {
+	if historyFile == "" {
+		historyFile, err = history.DefaultFile()
+	}
+
+	return err
+}
+
+// appends health status of the region to the history store and drops
+// records beyond the retention, failure to keep the history is reported
+// but it does not fail the check
+func appendHistory(region types.StatusRegion) {
+	if historyOff {
+		return
+	}
+
+	now := time.Now()
+	store := history.New(historyFile)
+	if err := store.Append(now, region); err != nil {
+		stderr(fmt.Sprintf("history is not kept: %s\n", err))
+		return
+	}
+
+	if historyRetention > 0 {
+		if err := store.Compact(now.Add(-historyRetention)); err != nil {
+			stderr(fmt.Sprintf("history is not compacted: %s\n", err))
+		}
+	}
+}
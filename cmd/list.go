@@ -13,13 +13,16 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/zalando/rds-health/internal/show"
-	"github.com/zalando/rds-health/internal/show/minimal"
-	"github.com/zalando/rds-health/internal/show/verbose"
+	"github.com/zalando/rds-health/internal/show/format"
 	"github.com/zalando/rds-health/internal/types"
 )
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	withFilterFlags(listCmd)
+	withGroupByFlag(listCmd)
+	withOutputFlag(listCmd, listOutput.Formats())
+	withFormatFlags(listCmd)
 	listCmd.InheritedFlags().SetAnnotation("database", cobra.BashCompOneRequiredFlag, []string{"false"})
 	listCmd.InheritedFlags().SetAnnotation("interval", cobra.BashCompOneRequiredFlag, []string{"false"})
 }
@@ -29,24 +32,43 @@ var listCmd = &cobra.Command{
 	Short: "list all database instances and clusters in AWS account",
 	Example: `
 rds-health list
+rds-health list --tag team=payments --name "payment-*"
+rds-health list --group-by team
+rds-health list -o yaml
+rds-health list -o markdown
+rds-health list --format '{{range nodes .}}{{.Name}} {{.Engine}}{{"\n"}}{{end}}'
 	`,
 	SilenceUsage: true,
+	PreRunE:      listOpts,
 	RunE:         WithService(list),
 	PostRun:      listPost,
 }
 
+func listOpts(cmd *cobra.Command, args []string) error {
+	return parseFormat()
+}
+
 func list(cmd *cobra.Command, args []string, api Service) error {
-	out := minimal.ShowConfigRegion
+	var out show.Printer[types.Region]
+	var err error
 	switch {
-	case outVerbose:
-		out = verbose.ShowConfigRegion
-	case outSilent:
-		out = show.None[types.Region]()
-	case outJsonify:
-		out = show.JSON[types.Region]()
+	case outTmpl != nil:
+		out = format.Show[types.Region](outTmpl)
+	case groupBy != "":
+		out, err = listGroups()
+	default:
+		out, err = lookupOutput(listOutput)
+	}
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter()
+	if err != nil {
+		return err
 	}
 
-	region, err := api.ShowRegion(cmd.Context())
+	region, err := api.ShowRegion(cmd.Context(), filter)
 	if err != nil {
 		return err
 	}
@@ -58,8 +80,19 @@ func list(cmd *cobra.Command, args []string, api Service) error {
 	return stdout(out.Show(*region))
 }
 
+func listGroups() (show.Printer[types.Region], error) {
+	out, err := lookupOutput(listGroupsOutput)
+	if err != nil {
+		return nil, fmt.Errorf("group-by: %w", err)
+	}
+
+	return show.ContraMap[[]types.RegionGroup, types.Region]{T: out}.FMap(
+		func(r types.Region) []types.RegionGroup { return r.GroupBy(groupBy) },
+	), nil
+}
+
 func listPost(cmd *cobra.Command, args []string) {
-	if !outJsonify {
+	if outHuman() {
 		stderr("\n(use \"rds-health check\" to check health status of instances)\n")
 	}
 }
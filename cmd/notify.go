@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/cache"
+	"github.com/zalando/rds-health/internal/notify"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	notifyWebhooks []string
+	notifySlack    []string
+	notifyOn       string
+	notifyTemplate string
+	notifyDedup    time.Duration
+	notifier       *notify.Notifier
+)
+
+// declares flags to send notifications about health status
+func withNotifyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&notifyWebhooks, "webhook", nil, "send notifications as JSON to the webhook URL (repeatable)")
+	cmd.Flags().StringArrayVar(&notifySlack, "slack", nil, "send notifications to the Slack incoming webhook URL (repeatable)")
+	cmd.Flags().StringVar(&notifyOn, "notify-on", "fail", "notify about instances that warn, fail or change status (warn|fail|change)")
+	cmd.Flags().StringVar(&notifyTemplate, "notify-template", notify.DefaultTemplate, "Go template of notification text")
+	cmd.Flags().DurationVar(&notifyDedup, "notify-dedup", time.Hour, "suppress repeated notifications within the period")
+}
+
+// decodes notification flags, the notifier is nil if no webhooks are defined
+func parseNotify() (*notify.Notifier, error) {
+	sinks := make([]notify.Sink, 0)
+	for _, url := range notifyWebhooks {
+		sinks = append(sinks, notify.Webhook{URL: url})
+	}
+	for _, url := range notifySlack {
+		sinks = append(sinks, notify.Slack{URL: url})
+	}
+
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	policy, err := notify.ParsePolicy(notifyOn)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := notify.Template(notifyTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []notify.Option{
+		notify.WithPolicy(policy),
+		notify.WithTemplate(tmpl),
+		notify.WithDedup(notifyDedup),
+	}
+
+	// state of notifications is persisted for deduplication across runs
+	if dir, err := cache.DefaultDir(); err == nil {
+		file, err := notifyState(dir)
+		if err != nil {
+			stderr(fmt.Sprintf("notifications are not deduplicated across runs: %s\n", err))
+		} else {
+			opts = append(opts, notify.WithState(file))
+		}
+	}
+
+	return notify.New(sinks, opts...)
+}
+
+// resolves the file of notification state, the state is isolated per region,
+// credentials and filter (same as the cache of AWS APIs), so that accounts
+// or checks of other instances never suppress each other's notifications
+func notifyState(dir string) (string, error) {
+	conf, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := conf.Credentials.Retrieve(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintln(hash, conf.Region, creds.AccessKeyID)
+	fmt.Fprintln(hash, rootDatabase, filterTags, filterAttrs, filterEngines, filterClasses, filterClusters, filterNames, filterPattern)
+	space := hex.EncodeToString(hash.Sum(nil)[:8])
+
+	return filepath.Join(dir, "notify", space+".json"), nil
+}
+
+// sends notifications about health status of the region
+func notifyRegion(ctx context.Context, region types.StatusRegion) error {
+	if notifier == nil {
+		return nil
+	}
+
+	return notifier.Notify(ctx, time.Now(), region)
+}
@@ -0,0 +1,266 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/show/compact"
+	"github.com/zalando/rds-health/internal/show/format"
+	"github.com/zalando/rds-health/internal/show/html"
+	"github.com/zalando/rds-health/internal/show/junit"
+	"github.com/zalando/rds-health/internal/show/minimal"
+	"github.com/zalando/rds-health/internal/show/sarif"
+	"github.com/zalando/rds-health/internal/show/table"
+	"github.com/zalando/rds-health/internal/show/verbose"
+	"github.com/zalando/rds-health/internal/types"
+	"golang.org/x/term"
+)
+
+var (
+	outFormat   string
+	outChart    string
+	outTmplText string
+	outTmplFile string
+	outTmpl     *template.Template
+)
+
+//
+// Registry of printers, output formats supported by commands
+//
+
+var (
+	listOutput = show.Registry[types.Region]{
+		"minimal":  minimal.ShowConfigRegion,
+		"verbose":  verbose.ShowConfigRegion,
+		"json":     show.JSON[types.Region](),
+		"yaml":     show.YAML[types.Region](),
+		"csv":      table.ShowConfigRegion(table.CSV),
+		"tsv":      table.ShowConfigRegion(table.TSV),
+		"markdown": table.ShowConfigRegion(table.Markdown),
+		"none":     show.None[types.Region](),
+	}
+
+	listGroupsOutput = show.Registry[[]types.RegionGroup]{
+		"minimal": minimal.ShowConfigRegionGroups,
+		"verbose": verbose.ShowConfigRegionGroups,
+		"json":    show.JSON[[]types.RegionGroup](),
+		"yaml":    show.YAML[[]types.RegionGroup](),
+		"none":    show.None[[]types.RegionGroup](),
+	}
+
+	checkRegionOutput = show.Registry[types.StatusRegion]{
+		"minimal":  minimal.ShowHealthRegion,
+		"verbose":  minimal.ShowHealthRegionWithRules,
+		"compact":  compact.ShowHealthRegion,
+		"json":     show.JSON[types.StatusRegion](),
+		"yaml":     show.YAML[types.StatusRegion](),
+		"junit":    junit.ShowHealthRegion,
+		"sarif":    sarif.ShowHealthRegion,
+		"html":     html.ShowHealthRegion,
+		"csv":      table.ShowHealthRegion(table.CSV),
+		"tsv":      table.ShowHealthRegion(table.TSV),
+		"markdown": table.ShowHealthRegion(table.Markdown),
+		"none":     show.None[types.StatusRegion](),
+	}
+
+	checkGroupsOutput = show.Registry[[]types.StatusRegionGroup]{
+		"minimal": minimal.ShowHealthRegionGroups,
+		"verbose": minimal.ShowHealthRegionWithRulesGroups,
+		"json":    show.JSON[[]types.StatusRegionGroup](),
+		"yaml":    show.YAML[[]types.StatusRegionGroup](),
+		"none":    show.None[[]types.StatusRegionGroup](),
+	}
+
+	checkNodeOutput = show.Registry[types.StatusNode]{
+		"minimal":  minimal.ShowHealthNode,
+		"verbose":  verbose.ShowHealthNode,
+		"compact":  compact.ShowHealthNode,
+		"json":     show.JSON[types.StatusNode](),
+		"yaml":     show.YAML[types.StatusNode](),
+		"junit":    junit.ShowHealthNode,
+		"sarif":    sarif.ShowHealthNode,
+		"html":     html.ShowHealthNode,
+		"csv":      table.ShowHealthNode(table.CSV),
+		"tsv":      table.ShowHealthNode(table.TSV),
+		"markdown": table.ShowHealthNode(table.Markdown),
+		"none":     show.None[types.StatusNode](),
+	}
+
+	showNodeOutput = show.Registry[types.StatusNode]{
+		"minimal":  minimal.ShowValueNode,
+		"verbose":  verbose.ShowValueNode,
+		"compact":  compact.ShowValueNode,
+		"json":     show.JSON[types.StatusNode](),
+		"yaml":     show.YAML[types.StatusNode](),
+		"html":     html.ShowValueNode,
+		"csv":      table.ShowValueNode(table.CSV),
+		"tsv":      table.ShowValueNode(table.TSV),
+		"markdown": table.ShowValueNode(table.Markdown),
+		"none":     show.None[types.StatusNode](),
+	}
+
+	showClusterOutput = show.Registry[types.StatusCluster]{
+		"minimal":  minimal.ShowValueCluster,
+		"json":     show.JSON[types.StatusCluster](),
+		"yaml":     show.YAML[types.StatusCluster](),
+		"csv":      table.ShowValueCluster(table.CSV),
+		"tsv":      table.ShowValueCluster(table.TSV),
+		"markdown": table.ShowValueCluster(table.Markdown),
+		"none":     show.None[types.StatusCluster](),
+	}
+
+	showRegionOutput = show.Registry[types.StatusRegion]{
+		"minimal":  minimal.ShowValueRegion,
+		"json":     show.JSON[types.StatusRegion](),
+		"yaml":     show.YAML[types.StatusRegion](),
+		"csv":      table.ShowValueRegion(table.CSV),
+		"tsv":      table.ShowValueRegion(table.TSV),
+		"markdown": table.ShowValueRegion(table.Markdown),
+		"none":     show.None[types.StatusRegion](),
+	}
+
+	auditNodeOutput = show.Registry[types.StatusNode]{
+		"minimal": minimal.ShowAuditNode,
+		"verbose": verbose.ShowAuditNode,
+		"json":    show.JSON[types.StatusNode](),
+		"yaml":    show.YAML[types.StatusNode](),
+		"junit":   junit.ShowHealthNode,
+		"sarif":   sarif.ShowHealthNode,
+		"none":    show.None[types.StatusNode](),
+	}
+
+	rightsizeRegionOutput = show.Registry[[]types.Rightsize]{
+		"minimal": minimal.ShowRightsizeRegion,
+		"verbose": verbose.ShowRightsizeRegion,
+		"json":    show.JSON[[]types.Rightsize](),
+		"yaml":    show.YAML[[]types.Rightsize](),
+		"none":    show.None[[]types.Rightsize](),
+	}
+
+	rightsizeNodeOutput = show.Registry[types.Rightsize]{
+		"minimal": minimal.ShowRightsizeNode,
+		"verbose": verbose.ShowRightsizeNode,
+		"json":    show.JSON[types.Rightsize](),
+		"yaml":    show.YAML[types.Rightsize](),
+		"none":    show.None[types.Rightsize](),
+	}
+
+	diffOutput = show.Registry[[]types.Change]{
+		"minimal": minimal.ShowChanges,
+		"json":    show.JSON[[]types.Change](),
+		"yaml":    show.YAML[[]types.Change](),
+		"none":    show.None[[]types.Change](),
+	}
+
+	historyOutput = show.Registry[types.History]{
+		"minimal": minimal.ShowHistory,
+		"json":    show.JSON[types.History](),
+		"yaml":    show.YAML[types.History](),
+		"none":    show.None[types.History](),
+	}
+
+	watchOutput = show.Registry[[]types.Transition]{
+		"minimal": minimal.ShowTransitions,
+		"json":    show.Seq[types.Transition]{T: show.JSONL[types.Transition]()},
+		"none":    show.None[[]types.Transition](),
+	}
+)
+
+// audit of region in verbose mode shows the column per rule
+func auditRegionOutput(ids []string) show.Registry[types.StatusRegion] {
+	return show.Registry[types.StatusRegion]{
+		"minimal": minimal.ShowAuditRegion,
+		"verbose": minimal.ShowAuditRegionWithRules(ids),
+		"json":    show.JSON[types.StatusRegion](),
+		"yaml":    show.YAML[types.StatusRegion](),
+		"junit":   junit.ShowHealthRegion,
+		"sarif":   sarif.ShowHealthRegion,
+		"none":    show.None[types.StatusRegion](),
+	}
+}
+
+// declares flag to select the output format, the help lists formats
+// supported by the command
+func withOutputFlag(cmd *cobra.Command, formats ...[]string) {
+	seq := slices.Concat(formats...)
+	slices.Sort(seq)
+
+	cmd.Flags().StringVarP(&outFormat, "output", "o", "minimal", "output format ("+strings.Join(slices.Compact(seq), ", ")+")")
+}
+
+// lookup the printer of output format, the error is reported for
+// formats that are not supported by the command
+func lookupOutput[T any](registry show.Registry[T]) (show.Printer[T], error) {
+	return registry.Lookup(outFormat)
+}
+
+// output for humans, hints are printed
+func outHuman() bool {
+	return outTmpl == nil && outChart == "" && slices.Contains([]string{"minimal", "verbose", "compact"}, outFormat)
+}
+
+// declares flags to output using Go template
+func withFormatFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outTmplText, "format", "", "output using Go template (e.g. '{{.Node.Name}} {{status .Status}}')")
+	cmd.Flags().StringVar(&outTmplFile, "format-file", "", "output using Go template from the file")
+	cmd.MarkFlagsMutuallyExclusive("format", "format-file")
+	cmd.MarkFlagsMutuallyExclusive("format", "output")
+	cmd.MarkFlagsMutuallyExclusive("format-file", "output")
+}
+
+// parses the template of output, if defined
+func parseFormat() error {
+	text := outTmplText
+	if outTmplFile != "" {
+		b, err := os.ReadFile(outTmplFile)
+		if err != nil {
+			return err
+		}
+		text = string(b)
+	}
+
+	if text == "" {
+		return nil
+	}
+
+	t, err := format.Template(text)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	outTmpl = t
+	return nil
+}
+
+// declares flag to draw chart of the metric
+func withChartFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outChart, "chart", "", "draw chart of the metric, either rule id (e.g. D3), metric name or description")
+	cmd.MarkFlagsMutuallyExclusive("chart", "output")
+}
+
+// width of terminal, defaults to 80 columns
+func termWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+
+	return 80
+}
@@ -13,8 +13,8 @@ import (
 	"os"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/schollz/progressbar/v3"
+	"github.com/zalando/rds-health/internal/audit"
 	"github.com/zalando/rds-health/internal/service"
 	"github.com/zalando/rds-health/internal/types"
 )
@@ -53,10 +53,17 @@ func spinner[T any](bar *progressbar.ProgressBar, f func() (T, error)) (T, error
 //
 
 type Service interface {
-	CheckHealthRegion(ctx context.Context, interval time.Duration) (*types.StatusRegion, error)
+	CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error)
 	CheckHealthNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error)
-	ShowRegion(ctx context.Context) (*types.Region, error)
+	ShowRegion(ctx context.Context, filter types.Filter) (*types.Region, error)
 	ShowNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error)
+	ShowUsageCluster(ctx context.Context, id string, interval time.Duration) (*types.StatusCluster, error)
+	ShowUsageRegion(ctx context.Context, filter types.Filter, metrics []string, interval time.Duration) (*types.StatusRegion, error)
+	AuditRegion(ctx context.Context, filter types.Filter, rules []audit.Rule) (*types.StatusRegion, error)
+	AuditNode(ctx context.Context, name string, rules []audit.Rule) (*types.StatusNode, error)
+	RightsizeRegion(ctx context.Context, filter types.Filter, interval time.Duration) ([]types.Rightsize, error)
+	RightsizeNode(ctx context.Context, name string, interval time.Duration) (*types.Rightsize, error)
+	ExportNode(ctx context.Context, name string, interval time.Duration, metrics []string) ([]types.Point, error)
 }
 
 type serviceWithSpinner struct {
@@ -64,7 +71,7 @@ type serviceWithSpinner struct {
 	bar *progressbar.ProgressBar
 }
 
-func newServiceWithSpinner(conf aws.Config) Service {
+func newServiceWithSpinner(providers service.Providers) Service {
 	bar := progressbar.NewOptions(-1,
 		progressbar.OptionShowBytes(false),
 		progressbar.OptionClearOnFinish(),
@@ -74,14 +81,14 @@ func newServiceWithSpinner(conf aws.Config) Service {
 	)
 
 	return serviceWithSpinner{
-		Service: service.New(conf, bar),
+		Service: service.New(providers, bar),
 		bar:     bar,
 	}
 }
 
-func (s serviceWithSpinner) CheckHealthRegion(ctx context.Context, interval time.Duration) (*types.StatusRegion, error) {
+func (s serviceWithSpinner) CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error) {
 	return spinner(s.bar, func() (*types.StatusRegion, error) {
-		return s.Service.CheckHealthRegion(ctx, interval)
+		return s.Service.CheckHealthRegion(ctx, filter, interval)
 	})
 }
 
@@ -92,9 +99,9 @@ func (s serviceWithSpinner) CheckHealthNode(ctx context.Context, name string, in
 
 }
 
-func (s serviceWithSpinner) ShowRegion(ctx context.Context) (*types.Region, error) {
+func (s serviceWithSpinner) ShowRegion(ctx context.Context, filter types.Filter) (*types.Region, error) {
 	return spinner(s.bar, func() (*types.Region, error) {
-		return s.Service.ShowRegion(ctx)
+		return s.Service.ShowRegion(ctx, filter)
 	})
 }
 
@@ -103,3 +110,45 @@ func (s serviceWithSpinner) ShowNode(ctx context.Context, name string, interval
 		return s.Service.ShowNode(ctx, name, interval)
 	})
 }
+
+func (s serviceWithSpinner) ShowUsageCluster(ctx context.Context, id string, interval time.Duration) (*types.StatusCluster, error) {
+	return spinner(s.bar, func() (*types.StatusCluster, error) {
+		return s.Service.ShowUsageCluster(ctx, id, interval)
+	})
+}
+
+func (s serviceWithSpinner) ShowUsageRegion(ctx context.Context, filter types.Filter, metrics []string, interval time.Duration) (*types.StatusRegion, error) {
+	return spinner(s.bar, func() (*types.StatusRegion, error) {
+		return s.Service.ShowUsageRegion(ctx, filter, metrics, interval)
+	})
+}
+
+func (s serviceWithSpinner) AuditRegion(ctx context.Context, filter types.Filter, rules []audit.Rule) (*types.StatusRegion, error) {
+	return spinner(s.bar, func() (*types.StatusRegion, error) {
+		return s.Service.AuditRegion(ctx, filter, rules)
+	})
+}
+
+func (s serviceWithSpinner) AuditNode(ctx context.Context, name string, rules []audit.Rule) (*types.StatusNode, error) {
+	return spinner(s.bar, func() (*types.StatusNode, error) {
+		return s.Service.AuditNode(ctx, name, rules)
+	})
+}
+
+func (s serviceWithSpinner) RightsizeRegion(ctx context.Context, filter types.Filter, interval time.Duration) ([]types.Rightsize, error) {
+	return spinner(s.bar, func() ([]types.Rightsize, error) {
+		return s.Service.RightsizeRegion(ctx, filter, interval)
+	})
+}
+
+func (s serviceWithSpinner) RightsizeNode(ctx context.Context, name string, interval time.Duration) (*types.Rightsize, error) {
+	return spinner(s.bar, func() (*types.Rightsize, error) {
+		return s.Service.RightsizeNode(ctx, name, interval)
+	})
+}
+
+func (s serviceWithSpinner) ExportNode(ctx context.Context, name string, interval time.Duration, metrics []string) ([]types.Point, error) {
+	return spinner(s.bar, func() ([]types.Point, error) {
+		return s.Service.ExportNode(ctx, name, interval, metrics)
+	})
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	rightsizeDuration time.Duration
+	rightsizeFilter   types.Filter
+)
+
+func init() {
+	rootCmd.AddCommand(rightsizeCmd)
+	withFilterFlags(rightsizeCmd)
+	withOutputFlag(rightsizeCmd, rightsizeRegionOutput.Formats(), rightsizeNodeOutput.Formats())
+}
+
+var rightsizeCmd = &cobra.Command{
+	Use:   "rightsize",
+	Short: "recommend instance class using resource utilization",
+	Long:  "recommend smaller, larger or different family instance class using cpu, db load and memory utilization from AWS Performance Insights",
+	Example: `
+rds-health rightsize -t 7d
+rds-health rightsize -t 7d -n myrds -o verbose
+rds-health rightsize -t 7d --tag team=payments
+	`,
+	SilenceUsage: true,
+	PreRunE:      rightsizeOpts,
+	RunE:         WithService(rightsize),
+	PostRunE:     rightsizePost,
+}
+
+func rightsizeOpts(cmd *cobra.Command, args []string) (err error) {
+	rightsizeDuration, err = parseInterval()
+	if err != nil {
+		return err
+	}
+
+	rightsizeFilter, err = parseFilter()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func rightsizePost(cmd *cobra.Command, args []string) error {
+	if (rootDatabase == "") && outFormat != "verbose" && outHuman() {
+		stderr("\n(use \"rds-health rightsize -n NAME\" to see all options for the instance)\n")
+	}
+
+	if rootDatabase != "" && outFormat != "verbose" && outHuman() {
+		stderr("\n(use \"rds-health rightsize -o verbose -n " + rootDatabase + "\" to see reasoning)\n")
+	}
+
+	return nil
+}
+
+func rightsize(cmd *cobra.Command, args []string, api Service) error {
+	if rootDatabase == "" {
+		out, err := lookupOutput(rightsizeRegionOutput)
+		if err != nil {
+			return err
+		}
+
+		seq, err := api.RightsizeRegion(cmd.Context(), rightsizeFilter, rightsizeDuration)
+		if err != nil {
+			return err
+		}
+
+		return stdout(out.Show(seq))
+	}
+
+	out, err := lookupOutput(rightsizeNodeOutput)
+	if err != nil {
+		return err
+	}
+
+	sizing, err := api.RightsizeNode(cmd.Context(), rootDatabase, rightsizeDuration)
+	if err != nil {
+		return err
+	}
+
+	return stdout(out.Show(*sizing))
+}
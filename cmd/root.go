@@ -10,27 +10,35 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/cache"
 	"github.com/zalando/rds-health/internal/service"
 	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
 )
 
-// Execute is entry point for cobra cli application
+// Execute is entry point for cobra cli application, the process exits
+// with the code of the command's result (see ExitCode).
 func Execute(vsn string) {
 	rootCmd.Version = vsn
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+
+	var status *ExitStatus
+	if err != nil && !errors.As(err, &status) {
 		e := err.Error()
 		fmt.Println(strings.ToUpper(e[:1]) + e[1:])
-		os.Exit(1)
 	}
+
+	os.Exit(ExitCode(err))
 }
 
 var (
@@ -40,6 +48,10 @@ var (
 	outJsonify   bool
 	rootDatabase string
 	rootInterval string
+	rootNoCache  bool
+	rootCacheTTL time.Duration
+	rootRecord   string
+	rootReplay   string
 )
 
 func init() {
@@ -47,9 +59,16 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&outVerbose, "verbose", "v", false, "output detailed information")
 	rootCmd.PersistentFlags().BoolVar(&outSilent, "silent", false, "output nothing")
 	rootCmd.PersistentFlags().BoolVar(&outJsonify, "json", false, "output raw json")
+	rootCmd.PersistentFlags().MarkDeprecated("verbose", "use -o verbose instead")
+	rootCmd.PersistentFlags().MarkDeprecated("json", "use -o json instead")
 	//
 	rootCmd.PersistentFlags().StringVarP(&rootDatabase, "database", "n", "", "AWS RDS database name")
 	rootCmd.PersistentFlags().StringVarP(&rootInterval, "interval", "t", "24h", "time interval either in minutes (m), hours (h), days (d) or week (w)")
+	rootCmd.PersistentFlags().BoolVar(&rootNoCache, "no-cache", false, "do not use cached responses of AWS APIs")
+	rootCmd.PersistentFlags().DurationVar(&rootCacheTTL, "cache-ttl", 15*time.Minute, "time-to-live of cached responses of AWS APIs")
+	rootCmd.PersistentFlags().StringVar(&rootRecord, "record", "", "record requests and responses of AWS APIs, appending them to the file")
+	rootCmd.PersistentFlags().StringVar(&rootReplay, "replay", "", "replay responses of AWS APIs from the recorded file, runs offline")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
 
 }
 
@@ -79,7 +98,7 @@ the utility applies softening on raw data to remove outliers.
 
     FAIL my-example-database
 
-    (use "rds-health check -v -n my-example-database" to see full report)
+    (use "rds-health check -o verbose -n my-example-database" to see full report)
 
 
 Health rules
@@ -147,11 +166,19 @@ Examples:
   rds-health check -t 7d
   rds-health check -t 7d -n my-example-database
   rds-health show -t 7d -n my-example-database
+  rds-health audit
+  rds-health rightsize -t 7d
+  rds-health export -t 7d -n my-example-database -m db.load
+  rds-health serve -t 1h --listen :9100
+  rds-health watch -t 1h -n my-example-database
+  rds-health diff before.json after.json
+  rds-health history -n my-example-database
   rds-health list
 
 `,
 	Run:              root,
 	PersistentPreRun: setup,
+	SilenceErrors:    true,
 }
 
 func root(cmd *cobra.Command, args []string) {
@@ -162,6 +189,22 @@ func setup(cmd *cobra.Command, args []string) {
 	if outColored {
 		show.SCHEMA = show.SCHEMA_COLOR
 	}
+
+	// legacy flags are aliases of output formats
+	if !cmd.Flags().Changed("output") {
+		switch {
+		case outVerbose:
+			outFormat = "verbose"
+		case outSilent:
+			outFormat = "none"
+		case outJsonify:
+			outFormat = "json"
+		}
+	}
+
+	if outFormat == "none" {
+		outSilent = true
+	}
 }
 
 //
@@ -170,23 +213,7 @@ func setup(cmd *cobra.Command, args []string) {
 
 // decodes human-readable time interval to time.Duration
 func parseInterval() (time.Duration, error) {
-	v, err := strconv.Atoi(rootInterval[0 : len(rootInterval)-1])
-	if err != nil {
-		return 0, err
-	}
-
-	switch rootInterval[len(rootInterval)-1] {
-	case 'm':
-		return time.Duration(v) * time.Minute, nil
-	case 'h':
-		return time.Duration(v) * time.Hour, nil
-	case 'd':
-		return time.Duration(v) * time.Hour * 24, nil
-	case 'w':
-		return time.Duration(v) * time.Hour * 24 * 7, nil
-	default:
-		return 0, fmt.Errorf("time scale %s is not supported", rootInterval)
-	}
+	return types.ParseInterval(rootInterval)
 }
 
 // outputs result of printer to stdout
@@ -211,6 +238,21 @@ func stderr(data string) {
 
 func WithService(
 	f func(cmd *cobra.Command, args []string, api Service) error,
+) func(cmd *cobra.Command, args []string) error {
+	return withService(false, f)
+}
+
+// WithDaemon is WithService for long-running commands, which do not show
+// the progress
+func WithDaemon(
+	f func(cmd *cobra.Command, args []string, api Service) error,
+) func(cmd *cobra.Command, args []string) error {
+	return withService(true, f)
+}
+
+func withService(
+	daemon bool,
+	f func(cmd *cobra.Command, args []string, api Service) error,
 ) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		conf, err := config.LoadDefaultConfig(context.Background())
@@ -218,15 +260,60 @@ func WithService(
 			return err
 		}
 
+		providers, err := newProviders(conf)
+		if err != nil {
+			return err
+		}
+
 		var api Service
 
 		switch {
-		case outSilent:
-			api = service.New(conf, silentbar(0))
+		case daemon || outSilent:
+			api = service.New(providers, silentbar(0))
 		default:
-			api = newServiceWithSpinner(conf)
+			api = newServiceWithSpinner(providers)
 		}
 
 		return f(cmd, args, api)
 	}
 }
+
+func newProviders(conf aws.Config) (service.Providers, error) {
+	if rootReplay != "" {
+		f, err := os.Open(rootReplay)
+		if err != nil {
+			return service.Providers{}, err
+		}
+		defer f.Close()
+
+		return service.Replay(f)
+	}
+
+	providers := service.NewProviders(conf)
+
+	if !rootNoCache && rootCacheTTL > 0 {
+		dir, err := cache.DefaultDir()
+		if err != nil {
+			return providers, err
+		}
+
+		providers, err = providers.WithCache(conf, dir, rootCacheTTL)
+		if err != nil {
+			return providers, err
+		}
+	}
+
+	// the file remains open until the process exits, interactions are
+	// written as soon as they happen. Recordings of multiple commands are
+	// appended to the same file.
+	if rootRecord != "" {
+		f, err := os.OpenFile(rootRecord, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return providers, err
+		}
+
+		providers = providers.WithRecorder(f)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,139 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/prometheus"
+	"github.com/zalando/rds-health/internal/server"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	serveDuration time.Duration
+	serveFilter   types.Filter
+	serveListen   string
+	serveRefresh  time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	withFilterFlags(serveCmd)
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":9100", "address to listen for Prometheus scrapes")
+	serveCmd.Flags().DurationVar(&serveRefresh, "refresh", 15*time.Minute, "period of health status refresh")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "serve Prometheus metrics and HTTP JSON API",
+	Long: `periodically check health status of the region and expose it as Prometheus
+metrics at /metrics, scrapes are served from the latest results.
+
+The HTTP JSON API mirrors commands of the utility, query parameters are
+interval, profile (cpu, memory, storage, database, cluster or rule ids) and filters
+(tag, where, engine, class, cluster, name, name-regex):
+
+  GET /v1/check          health status of the region
+  GET /v1/check/{name}   health status of the instance
+  GET /v1/list           instances and clusters of the region
+  GET /v1/show/{name}    resource utilization of the instance
+  GET /healthz           liveness probe
+  GET /readyz            readiness probe
+`,
+	Example: `
+rds-health serve --listen :9100 -t 1h
+rds-health serve --listen :9100 -t 1h --refresh 5m --tag team=payments
+curl -s "localhost:9100/v1/check?interval=7d&profile=storage&tag=team=payments"
+	`,
+	SilenceUsage: true,
+	PreRunE:      serveOpts,
+	RunE:         WithDaemon(serve),
+}
+
+func serveOpts(cmd *cobra.Command, args []string) (err error) {
+	serveDuration, err = parseInterval()
+	if err != nil {
+		return err
+	}
+
+	serveFilter, err = parseFilter()
+	if err != nil {
+		return err
+	}
+
+	if serveRefresh < time.Minute {
+		return fmt.Errorf("refresh period %s is too short, use 1m or longer", serveRefresh)
+	}
+
+	// each refresh observes the current status, responses of AWS APIs
+	// cached by the previous refresh would delay metrics by a period
+	rootNoCache = true
+
+	return nil
+}
+
+func serve(cmd *cobra.Command, args []string, api Service) error {
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	exporter := prometheus.New(api, serveFilter, serveDuration)
+	go exporter.Run(ctx, serveRefresh, func(err error) {
+		stderr(fmt.Sprintf("%s refresh failed: %s\n", time.Now().Format(time.RFC3339), err))
+	})
+
+	// the server is ready if AWS credentials are available
+	conf, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	ready := func(ctx context.Context) error {
+		if rootReplay != "" {
+			return nil
+		}
+
+		_, err := conf.Credentials.Retrieve(ctx)
+		return err
+	}
+
+	mux := server.New(api, ready)
+	mux.Handle("GET /metrics", exporter)
+
+	srv := &http.Server{
+		Addr:              serveListen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	stderr(fmt.Sprintf("serving metrics and api at %s\n", serveListen))
+
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
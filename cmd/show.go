@@ -9,35 +9,62 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/rules"
 	"github.com/zalando/rds-health/internal/show"
-	"github.com/zalando/rds-health/internal/show/minimal"
+	"github.com/zalando/rds-health/internal/show/format"
 	"github.com/zalando/rds-health/internal/show/verbose"
 	"github.com/zalando/rds-health/internal/types"
 )
 
 var (
 	showDuration time.Duration
+	showFilter   types.Filter
+	showMetrics  []string
 )
 
+// metrics of region-wide utilization table shown by default
+var showDefaultMetrics = []string{
+	"os.cpuUtilization.total",
+	"db.Transactions.xact_commit",
+	"db.SQL.tup_fetched",
+	"os.memory.free",
+}
+
 func init() {
 	rootCmd.AddCommand(showCmd)
+	withOutputFlag(showCmd, showNodeOutput.Formats(), showClusterOutput.Formats(), showRegionOutput.Formats())
+	withChartFlag(showCmd)
+	withFormatFlags(showCmd)
+	withFilterFlags(showCmd)
+	showCmd.Flags().StringSliceVar(&showMetrics, "metric", showDefaultMetrics, "metrics of region-wide utilization table, one column per metric")
 }
 
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "show resource utilization",
-	Long:  "show system resource utilization of RDS instance using AWS Performance Insights",
+	Long:  "show system resource utilization of RDS instance or cluster using AWS Performance Insights, the utilization of all instances in the region is shown if name is omitted",
 	Example: `
+rds-health show -t 7d
+rds-health show -t 7d --metric os.cpuUtilization.total,os.memory.free --engine aurora-postgresql
+rds-health show -n name-of-rds-cluster -t 7d
 rds-health show -n name-of-rds-instance -t 7d
 rds-health show -n name-of-rds-instance -t 7d -a max
+rds-health show -n name-of-rds-instance -t 7d --chart os.cpuUtilization.total
+rds-health show -n name-of-rds-instance -t 7d -o compact
+rds-health show -n name-of-rds-instance -t 7d -o html > usage.html
+rds-health show -n name-of-rds-instance -t 7d --format '{{range .Checks}}{{.Rule.About}}: {{unit .SoftMM.Avg .Rule.Unit}}{{"\n"}}{{end}}'
 	`,
 	SilenceUsage: true,
 	PreRunE:      usageOpts,
-	RunE:         WithService(showNode),
+	RunE:         WithService(showUsage),
 }
 
 func usageOpts(cmd *cobra.Command, args []string) (err error) {
@@ -46,25 +73,106 @@ func usageOpts(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	if rootDatabase == "" {
-		return fmt.Errorf("undefined database name")
+	showFilter, err = parseFilter()
+	if err != nil {
+		return err
+	}
+
+	if err := parseFormat(); err != nil {
+		return err
+	}
+
+	if outChart != "" && rootDatabase == "" {
+		return fmt.Errorf("chart requires database name, use -n NAME")
+	}
+
+	if cmd.Flags().Changed("metric") && rootDatabase != "" {
+		return fmt.Errorf("metric selects columns of region-wide utilization, it is not supported with -n NAME")
+	}
+
+	known := rules.UsageMetrics()
+	for _, metric := range showMetrics {
+		if !slices.Contains(known, metric) {
+			return fmt.Errorf("metric %s is not supported, use one of %s", metric, strings.Join(known, ", "))
+		}
 	}
 
 	return nil
 }
 
-func showNode(cmd *cobra.Command, args []string, api Service) error {
-	var out show.Printer[types.StatusNode] = minimal.ShowValueNode
+func showUsage(cmd *cobra.Command, args []string, api Service) error {
+	if rootDatabase == "" {
+		return showRegion(cmd, api)
+	}
+
+	var out show.Printer[types.StatusNode]
+	var err error
 	switch {
-	case outVerbose:
-		out = verbose.ShowValueNode
-	case outSilent:
-		out = show.None[types.StatusNode]()
-	case outJsonify:
-		out = show.JSON[types.StatusNode]()
+	case outChart != "":
+		out = verbose.ShowChartNode(outChart, termWidth())
+	case outTmpl != nil:
+		out = format.Show[types.StatusNode](outTmpl)
+	default:
+		out, err = lookupOutput(showNodeOutput)
+	}
+	if err != nil {
+		return err
 	}
 
 	usage, err := api.ShowNode(cmd.Context(), rootDatabase, showDuration)
+	if errors.Is(err, database.ErrNotFound) {
+		// the name is not an instance, it might be a cluster
+		return showCluster(cmd, api)
+	}
+	if err != nil {
+		return err
+	}
+
+	return stdout(out.Show(*usage))
+}
+
+func showCluster(cmd *cobra.Command, api Service) error {
+	if outChart != "" {
+		return fmt.Errorf("chart is supported for instances only, use -n with name of cluster member")
+	}
+
+	var out show.Printer[types.StatusCluster]
+	var err error
+	switch {
+	case outTmpl != nil:
+		out = format.Show[types.StatusCluster](outTmpl)
+	default:
+		out, err = lookupOutput(showClusterOutput)
+	}
+	if err != nil {
+		return err
+	}
+
+	usage, err := api.ShowUsageCluster(cmd.Context(), rootDatabase, showDuration)
+	if errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("%w: rds instance or cluster %s", database.ErrNotFound, rootDatabase)
+	}
+	if err != nil {
+		return err
+	}
+
+	return stdout(out.Show(*usage))
+}
+
+func showRegion(cmd *cobra.Command, api Service) error {
+	var out show.Printer[types.StatusRegion]
+	var err error
+	switch {
+	case outTmpl != nil:
+		out = format.Show[types.StatusRegion](outTmpl)
+	default:
+		out, err = lookupOutput(showRegionOutput)
+	}
+	if err != nil {
+		return err
+	}
+
+	usage, err := api.ShowUsageRegion(cmd.Context(), showFilter, showMetrics, showDuration)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/rds-health/internal/types"
+	"github.com/zalando/rds-health/internal/watch"
+)
+
+var (
+	watchDuration time.Duration
+	watchFilter   types.Filter
+	watchRefresh  time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	withFilterFlags(watchCmd)
+	withNotifyFlags(watchCmd)
+	withOutputFlag(watchCmd, watchOutput.Formats())
+	watchCmd.Flags().DurationVar(&watchRefresh, "refresh", 5*time.Minute, "period of health status refresh")
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "watch health status and report its changes",
+	Long:  "periodically check health status of database instance or the region and report transitions of status (e.g. PASS → WARN, WARN → FAIL, recoveries)",
+	Example: `
+rds-health watch -n myrds -t 1h --refresh 5m
+rds-health watch -t 1h --tag team=payments
+rds-health watch -t 1h -o json | jq .
+rds-health watch -t 1h --webhook https://example.com/hook --notify-on change
+	`,
+	SilenceUsage: true,
+	PreRunE:      watchOpts,
+	RunE:         WithDaemon(watchRegion),
+}
+
+func watchOpts(cmd *cobra.Command, args []string) (err error) {
+	watchDuration, err = parseInterval()
+	if err != nil {
+		return err
+	}
+
+	watchFilter, err = parseFilter()
+	if err != nil {
+		return err
+	}
+
+	notifier, err = parseNotify()
+	if err != nil {
+		return err
+	}
+
+	if watchRefresh < time.Minute {
+		return fmt.Errorf("refresh period %s is too short, use 1m or longer", watchRefresh)
+	}
+
+	// each refresh observes the current status, responses of AWS APIs
+	// cached by the previous refresh would hide transitions
+	rootNoCache = true
+
+	return nil
+}
+
+func watchRegion(cmd *cobra.Command, args []string, api Service) error {
+	out, err := lookupOutput(watchOutput)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	watcher := watch.New()
+	ticker := time.NewTicker(watchRefresh)
+	defer ticker.Stop()
+
+	for {
+		region, err := watchCheck(ctx, api)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			stderr(fmt.Sprintf("%s check failed: %s\n", time.Now().Format(time.RFC3339), err))
+		case err == nil:
+			if err := stdout(out.Show(watcher.Observe(time.Now(), *region))); err != nil {
+				return err
+			}
+
+			if err := notifyRegion(ctx, *region); err != nil && ctx.Err() == nil {
+				stderr(fmt.Sprintf("%s notification failed: %s\n", time.Now().Format(time.RFC3339), err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checks the health of database instance if defined, the region otherwise
+func watchCheck(ctx context.Context, api Service) (*types.StatusRegion, error) {
+	if rootDatabase == "" {
+		return api.CheckHealthRegion(ctx, watchFilter, watchDuration)
+	}
+
+	node, err := api.CheckHealthNode(ctx, rootDatabase, watchDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.StatusRegion{Status: node.Status, Nodes: []types.StatusNode{*node}}, nil
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// Eval of configuration rule for the node, the cluster is nil for
+// standalone instances. It returns status and observed value.
+type Eval func(types.Node, *types.Cluster) (types.StatusCode, string)
+
+// Rule of configuration audit
+type Rule struct {
+	ID    string // rule id
+	About string // short human readable description
+	Eval  Eval
+}
+
+// Select rules by its identity, all rules are selected if list is empty.
+// The rules from ignore list are excluded.
+func Select(only, ignore []string) ([]Rule, error) {
+	for _, id := range slices.Concat(only, ignore) {
+		if !slices.ContainsFunc(Rules, func(r Rule) bool { return r.ID == id }) {
+			return nil, fmt.Errorf("audit rule %s is not supported", id)
+		}
+	}
+
+	seq := make([]Rule, 0, len(Rules))
+	for _, rule := range Rules {
+		if len(only) != 0 && !slices.Contains(only, rule.ID) {
+			continue
+		}
+
+		if slices.Contains(ignore, rule.ID) {
+			continue
+		}
+
+		seq = append(seq, rule)
+	}
+
+	return seq, nil
+}
+
+// Audit is configuration rule engine over nodes and clusters
+type Audit struct {
+	rules []Rule
+}
+
+func New(rules ...Rule) *Audit {
+	return &Audit{rules: rules}
+}
+
+// Node audit
+func (audit *Audit) Node(node types.Node, cluster *types.Cluster) types.StatusNode {
+	checks := make([]types.Status, len(audit.rules))
+	code := types.STATUS_CODE_UNKNOWN
+
+	for i, rule := range audit.rules {
+		status, observed := types.STATUS_CODE_UNKNOWN, "-"
+		if node.Config != nil {
+			status, observed = rule.Eval(node, cluster)
+		}
+
+		checks[i] = types.Status{
+			Code:     status,
+			Rule:     types.Rule{ID: rule.ID, About: rule.About},
+			Observed: &observed,
+		}
+
+		if code < status {
+			code = status
+		}
+	}
+
+	return types.StatusNode{
+		Status: code,
+		Node:   &node,
+		Checks: checks,
+	}
+}
+
+// Cluster audit, each member is audited in the context of cluster
+func (audit *Audit) Cluster(cluster types.Cluster) types.StatusCluster {
+	status := types.StatusCluster{
+		Status:  types.STATUS_CODE_UNKNOWN,
+		Cluster: &cluster,
+		Writer:  make([]types.StatusNode, len(cluster.Writer)),
+		Reader:  make([]types.StatusNode, len(cluster.Reader)),
+	}
+
+	for i, node := range cluster.Writer {
+		status.Writer[i] = audit.Node(node, &cluster)
+		if status.Status < status.Writer[i].Status {
+			status.Status = status.Writer[i].Status
+		}
+	}
+
+	for i, node := range cluster.Reader {
+		status.Reader[i] = audit.Node(node, &cluster)
+		if status.Status < status.Reader[i].Status {
+			status.Status = status.Reader[i].Status
+		}
+	}
+
+	return status
+}
+
+// Region audit
+func (audit *Audit) Region(region types.Region) types.StatusRegion {
+	status := types.StatusRegion{
+		Status:   types.STATUS_CODE_UNKNOWN,
+		Clusters: make([]types.StatusCluster, len(region.Clusters)),
+		Nodes:    make([]types.StatusNode, len(region.Nodes)),
+	}
+
+	for i, cluster := range region.Clusters {
+		status.Clusters[i] = audit.Cluster(cluster)
+		if status.Status < status.Clusters[i].Status {
+			status.Status = status.Clusters[i].Status
+		}
+	}
+
+	for i, node := range region.Nodes {
+		status.Nodes[i] = audit.Node(node, nil)
+		if status.Status < status.Nodes[i].Status {
+			status.Status = status.Nodes[i].Status
+		}
+	}
+
+	return status
+}
+
+// IDs of all supported rules
+func IDs() string {
+	seq := make([]string, len(Rules))
+	for i, rule := range Rules {
+		seq[i] = rule.ID
+	}
+	return strings.Join(seq, ", ")
+}
@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/zalando/rds-health/internal/audit"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestSelect(t *testing.T) {
+	all, err := audit.Select(nil, nil)
+	switch {
+	case err != nil:
+		t.Errorf("should not fail with error %s", err)
+	case len(all) != len(audit.Rules):
+		t.Errorf("should select all rules")
+	}
+
+	seq, err := audit.Select([]string{"A1", "A2"}, []string{"A2"})
+	switch {
+	case err != nil:
+		t.Errorf("should not fail with error %s", err)
+	case len(seq) != 1 || seq[0].ID != "A1":
+		t.Errorf("should select rules and exclude ignored one |%v|", seq)
+	}
+
+	if _, err := audit.Select(nil, []string{"X1"}); err == nil {
+		t.Errorf("should fail on unknown rule")
+	}
+}
+
+func TestNode(t *testing.T) {
+	node := types.Node{
+		Name:    "a",
+		Zones:   types.AvailabilityZones{"eu-central-1a"},
+		Storage: &types.Storage{Type: "gp2"},
+		Config: &types.Config{
+			MultiAZ:            true,
+			BackupRetention:    3,
+			Insights:           false,
+			DeletionProtection: true,
+			Encrypted:          true,
+		},
+		Engine: &types.Engine{ID: "postgres", Version: "11.22"},
+	}
+
+	status := audit.New(audit.Rules...).Node(node, nil)
+
+	expect := map[string]types.StatusCode{
+		"A1": types.STATUS_CODE_SUCCESS,
+		"A2": types.STATUS_CODE_WARNING,
+		"A3": types.STATUS_CODE_FAILURE,
+		"A4": types.STATUS_CODE_SUCCESS,
+		"A5": types.STATUS_CODE_WARNING,
+		"A6": types.STATUS_CODE_SUCCESS,
+		"A7": types.STATUS_CODE_SUCCESS,
+		"A8": types.STATUS_CODE_FAILURE,
+	}
+
+	if status.Status != types.STATUS_CODE_FAILURE {
+		t.Errorf("should fail node |%s|", status.Status)
+	}
+
+	for _, check := range status.Checks {
+		if check.Code != expect[check.Rule.ID] {
+			t.Errorf("unexpected status of %s |%s|", check.Rule.ID, check.Code)
+		}
+	}
+}
+
+func TestNodeUnknown(t *testing.T) {
+	status := audit.New(audit.Rules...).Node(types.Node{Name: "a"}, nil)
+	if status.Status != types.STATUS_CODE_UNKNOWN {
+		t.Errorf("should not audit node without config |%s|", status.Status)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	node := func(name, az string) types.Node {
+		return types.Node{
+			Name:   name,
+			Zones:  types.AvailabilityZones{az},
+			Config: &types.Config{},
+		}
+	}
+
+	for cluster, expected := range map[*types.Cluster]types.StatusCode{
+		{
+			ID:     "single",
+			Writer: []types.Node{node("a", "eu-central-1a")},
+			Reader: []types.Node{node("b", "eu-central-1a")},
+		}: types.STATUS_CODE_FAILURE,
+		{
+			ID:     "multi",
+			Writer: []types.Node{node("a", "eu-central-1a")},
+			Reader: []types.Node{node("b", "eu-central-1b")},
+		}: types.STATUS_CODE_SUCCESS,
+	} {
+		status := audit.New(audit.MultiAZ).Cluster(*cluster)
+		if status.Status != expected {
+			t.Errorf("unexpected status of cluster %s |%s|", cluster.ID, status.Status)
+		}
+	}
+}
+
+func TestClusterConfig(t *testing.T) {
+	cluster := types.Cluster{
+		ID:     "c",
+		Writer: []types.Node{{Name: "a", Config: &types.Config{}}},
+		Config: &types.ClusterConfig{Encrypted: true, BackupRetention: 7, DeletionProtection: true},
+	}
+
+	status := audit.New(audit.BackupRetention, audit.DeletionProtection, audit.StorageEncryption).Cluster(cluster)
+	if status.Status != types.STATUS_CODE_SUCCESS {
+		t.Errorf("should audit members against cluster config |%+v|", status.Writer[0].Checks)
+	}
+
+	cluster.Config = &types.ClusterConfig{BackupRetention: 1}
+	for _, check := range audit.New(audit.BackupRetention, audit.StorageEncryption).Cluster(cluster).Writer[0].Checks {
+		if check.Code != types.STATUS_CODE_FAILURE {
+			t.Errorf("unexpected status of %s |%s|", check.Rule.ID, check.Code)
+		}
+	}
+}
+
+func TestEngineLifecycle(t *testing.T) {
+	for engine, expected := range map[types.Engine]types.StatusCode{
+		{ID: "postgres", Version: "11.22"}: types.STATUS_CODE_FAILURE,
+		{ID: "oracle-ee", Version: "19"}:   types.STATUS_CODE_UNKNOWN,
+	} {
+		node := types.Node{Name: "a", Engine: &engine, Config: &types.Config{}}
+		status := audit.New(audit.EngineLifecycle).Node(node, nil)
+		if status.Status != expected {
+			t.Errorf("unexpected status of %s %s |%s|", engine.ID, engine.Version, status.Status)
+		}
+	}
+}
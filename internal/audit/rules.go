@@ -0,0 +1,185 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zalando/rds-health/internal/lifecycle"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// Configuration best-practices. Backup retention, deletion protection and
+// storage encryption of cluster members are settings of the cluster.
+var (
+	MultiAZ = Rule{
+		ID:    "A1",
+		About: "multi-az deployment",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			// Aurora replicates storage across zones, the availability of
+			// compute depends on members deployed to different zones.
+			if cluster != nil {
+				zones := map[string]struct{}{}
+				for _, n := range slices.Concat(cluster.Writer, cluster.Reader) {
+					for _, az := range n.Zones {
+						zones[az] = struct{}{}
+					}
+				}
+
+				if len(zones) < 2 {
+					return types.STATUS_CODE_FAILURE, "single-az cluster"
+				}
+				return types.STATUS_CODE_SUCCESS, fmt.Sprintf("%d zones", len(zones))
+			}
+
+			if !node.Config.MultiAZ {
+				return types.STATUS_CODE_FAILURE, "single-az"
+			}
+			return types.STATUS_CODE_SUCCESS, "multi-az"
+		},
+	}
+
+	BackupRetention = Rule{
+		ID:    "A2",
+		About: "backup retention",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			days := node.Config.BackupRetention
+			if cluster != nil && cluster.Config != nil {
+				days = cluster.Config.BackupRetention
+			}
+			observed := fmt.Sprintf("%d days", days)
+			switch {
+			case days <= 1:
+				return types.STATUS_CODE_FAILURE, observed
+			case days < 7:
+				return types.STATUS_CODE_WARNING, observed
+			default:
+				return types.STATUS_CODE_SUCCESS, observed
+			}
+		},
+	}
+
+	PerformanceInsights = Rule{
+		ID:    "A3",
+		About: "performance insights",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			if !node.Config.Insights {
+				return types.STATUS_CODE_FAILURE, "disabled"
+			}
+			return types.STATUS_CODE_SUCCESS, fmt.Sprintf("%d days", node.Config.InsightsRetention)
+		},
+	}
+
+	DeletionProtection = Rule{
+		ID:    "A4",
+		About: "deletion protection",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			enabled := node.Config.DeletionProtection
+			if cluster != nil && cluster.Config != nil {
+				enabled = cluster.Config.DeletionProtection
+			}
+
+			if !enabled {
+				return types.STATUS_CODE_WARNING, "disabled"
+			}
+			return types.STATUS_CODE_SUCCESS, "enabled"
+		},
+	}
+
+	StorageType = Rule{
+		ID:    "A5",
+		About: "storage type",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			if node.Storage == nil {
+				return types.STATUS_CODE_UNKNOWN, "-"
+			}
+
+			switch node.Storage.Type {
+			case "standard":
+				return types.STATUS_CODE_FAILURE, node.Storage.Type
+			case "gp2":
+				return types.STATUS_CODE_WARNING, node.Storage.Type
+			default:
+				return types.STATUS_CODE_SUCCESS, node.Storage.Type
+			}
+		},
+	}
+
+	PublicAccess = Rule{
+		ID:    "A6",
+		About: "public accessibility",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			if node.Config.PubliclyAccessible {
+				return types.STATUS_CODE_FAILURE, "public"
+			}
+			return types.STATUS_CODE_SUCCESS, "private"
+		},
+	}
+
+	StorageEncryption = Rule{
+		ID:    "A7",
+		About: "storage encryption",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			encrypted := node.Config.Encrypted
+			if cluster != nil && cluster.Config != nil {
+				encrypted = cluster.Config.Encrypted
+			}
+
+			if !encrypted {
+				return types.STATUS_CODE_FAILURE, "unencrypted"
+			}
+			return types.STATUS_CODE_SUCCESS, "encrypted"
+		},
+	}
+
+	EngineLifecycle = Rule{
+		ID:    "A8",
+		About: "engine lifecycle",
+		Eval: func(node types.Node, cluster *types.Cluster) (types.StatusCode, string) {
+			if node.Engine == nil {
+				return types.STATUS_CODE_UNKNOWN, "-"
+			}
+
+			version, has := lifecycle.Default.Lookup(*node.Engine)
+			if !has {
+				return types.STATUS_CODE_UNKNOWN, node.Engine.Version
+			}
+
+			observed := fmt.Sprintf("%s, eos %s", node.Engine.Version, version.EndOfSupport.Format(time.DateOnly))
+			if version.Latest != "" && lifecycle.Compare(node.Engine.Version, version.Latest) < 0 {
+				observed += ", latest " + version.Latest
+			}
+
+			// warn about end of standard support half a year ahead
+			left := time.Until(version.EndOfSupport.Time)
+			switch {
+			case left <= 0:
+				return types.STATUS_CODE_FAILURE, observed
+			case left < 180*24*time.Hour:
+				return types.STATUS_CODE_WARNING, observed
+			default:
+				return types.STATUS_CODE_SUCCESS, observed
+			}
+		},
+	}
+)
+
+// All supported audit rules
+var Rules = []Rule{
+	MultiAZ,
+	BackupRetention,
+	PerformanceInsights,
+	DeletionProtection,
+	StorageType,
+	PublicAccess,
+	StorageEncryption,
+	EngineLifecycle,
+}
@@ -0,0 +1,234 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package compares health status with the baseline snapshot, which is
+// the JSON output of check command (either region or node). It reports
+// new failures, recoveries, significant changes of success rate or soft
+// min/avg/max per node and rule as well as added and removed nodes. Cluster
+// rules are compared per cluster.
+//
+
+// Options define significance of changes
+type Options struct {
+	SuccessRate float64 // absolute change of success rate, percentage points
+	Value       float64 // relative change of soft min, avg or max (e.g. 0.2 is 20%)
+}
+
+// Default options of significance
+var Default = Options{SuccessRate: 5.0, Value: 0.2}
+
+// Read snapshot of health status, the node is read as the region of one node
+func Read(r io.Reader) (types.StatusRegion, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return types.StatusRegion{}, err
+	}
+
+	var probe struct {
+		Node *types.Node `json:"node"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return types.StatusRegion{}, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	if probe.Node != nil {
+		var node types.StatusNode
+		if err := json.Unmarshal(b, &node); err != nil {
+			return types.StatusRegion{}, fmt.Errorf("invalid snapshot: %w", err)
+		}
+
+		return types.StatusRegion{Status: node.Status, Nodes: []types.StatusNode{node}}, nil
+	}
+
+	var region types.StatusRegion
+	if err := json.Unmarshal(b, &region); err != nil {
+		return types.StatusRegion{}, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	return region, nil
+}
+
+type member struct {
+	cluster string
+	node    types.StatusNode
+}
+
+func membersOf(region types.StatusRegion) []member {
+	seq := make([]member, 0)
+	for _, c := range region.Clusters {
+		for _, n := range slices.Concat(c.Writer, c.Reader) {
+			seq = append(seq, member{cluster: c.Cluster.ID, node: n})
+		}
+	}
+
+	for _, n := range region.Nodes {
+		seq = append(seq, member{node: n})
+	}
+
+	return seq
+}
+
+// Compare health status of the region with the baseline
+func Compare(baseline, region types.StatusRegion, opts Options) []types.Change {
+	prev := make(map[string]types.StatusNode)
+	for _, m := range membersOf(baseline) {
+		prev[m.node.Node.Name] = m.node
+	}
+
+	seq := make([]types.Change, 0)
+	seen := make(map[string]bool)
+
+	for _, m := range membersOf(region) {
+		name := m.node.Node.Name
+		seen[name] = true
+
+		old, has := prev[name]
+		if !has {
+			seq = append(seq, types.Change{Kind: types.CHANGE_ADDED, Node: name, Cluster: m.cluster, From: types.STATUS_CODE_UNKNOWN, To: m.node.Status})
+			continue
+		}
+
+		seq = append(seq, compareNode(old, m, opts)...)
+	}
+
+	for _, m := range membersOf(baseline) {
+		if name := m.node.Node.Name; !seen[name] {
+			seq = append(seq, types.Change{Kind: types.CHANGE_REMOVED, Node: name, Cluster: m.cluster, From: m.node.Status, To: types.STATUS_CODE_UNKNOWN})
+		}
+	}
+
+	// cluster rules of clusters known to the baseline, the change is
+	// reported for the cluster itself
+	for _, c := range region.Clusters {
+		at := slices.IndexFunc(baseline.Clusters, func(b types.StatusCluster) bool { return b.Cluster.ID == c.Cluster.ID })
+		if at == -1 {
+			continue
+		}
+
+		seq = append(seq, compareNode(clusterOf(baseline.Clusters[at]), member{cluster: c.Cluster.ID, node: clusterOf(c)}, opts)...)
+	}
+
+	return seq
+}
+
+// cluster rules as the health status of node named after the cluster
+func clusterOf(c types.StatusCluster) types.StatusNode {
+	return types.StatusNode{Status: c.Status, Node: &types.Node{Name: c.Cluster.ID}, Checks: c.Checks}
+}
+
+// CompareNode compares health status of the node with the baseline, other
+// nodes of the baseline are ignored
+func CompareNode(baseline types.StatusRegion, node types.StatusNode, opts Options) []types.Change {
+	m := member{cluster: node.Node.Cluster, node: node}
+	for _, b := range membersOf(baseline) {
+		if b.node.Node.Name == node.Node.Name {
+			return compareNode(b.node, m, opts)
+		}
+	}
+
+	return []types.Change{{Kind: types.CHANGE_ADDED, Node: node.Node.Name, Cluster: m.cluster, From: types.STATUS_CODE_UNKNOWN, To: node.Status}}
+}
+
+func compareNode(baseline types.StatusNode, m member, opts Options) []types.Change {
+	key := func(s types.Status) string { return s.Rule.ID + "/" + s.Rule.About }
+
+	prev := make(map[string]types.Status)
+	for _, s := range baseline.Checks {
+		prev[key(s)] = s
+	}
+
+	seq := make([]types.Change, 0)
+	change := func(rule types.Rule, from, to types.Status) {
+		deltas := deltasOf(from, to, opts)
+		if kind := kindOf(from.Code, to.Code, deltas); kind != "" {
+			seq = append(seq, types.Change{Kind: kind, Node: m.node.Node.Name, Cluster: m.cluster, Rule: &rule, From: from.Code, To: to.Code, Deltas: deltas})
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range m.node.Checks {
+		seen[key(s)] = true
+		change(s.Rule, prev[key(s)], s)
+	}
+
+	// rules are not evaluated anymore
+	for _, s := range baseline.Checks {
+		if !seen[key(s)] {
+			change(s.Rule, s, types.Status{Code: types.STATUS_CODE_UNKNOWN})
+		}
+	}
+
+	return seq
+}
+
+func kindOf(from, to types.StatusCode, deltas []types.Delta) types.ChangeKind {
+	switch {
+	case to > from && to > types.STATUS_CODE_SUCCESS:
+		return types.CHANGE_FAILURE
+	case to < from && from > types.STATUS_CODE_SUCCESS && to != types.STATUS_CODE_UNKNOWN:
+		return types.CHANGE_RECOVERY
+	case to != from || len(deltas) != 0:
+		return types.CHANGE_CHANGED
+	default:
+		return ""
+	}
+}
+
+func deltasOf(from, to types.Status, opts Options) []types.Delta {
+	seq := make([]types.Delta, 0)
+
+	if from.SuccessRate != nil && to.SuccessRate != nil {
+		a, b := *from.SuccessRate, *to.SuccessRate
+		if math.Abs(b-a) >= opts.SuccessRate {
+			seq = append(seq, types.Delta{Value: "success rate", From: a, To: b})
+		}
+	}
+
+	if from.SoftMM != nil && to.SoftMM != nil {
+		for _, v := range []struct {
+			value string
+			a, b  float64
+		}{
+			{"min", from.SoftMM.Min, to.SoftMM.Min},
+			{"avg", from.SoftMM.Avg, to.SoftMM.Avg},
+			{"max", from.SoftMM.Max, to.SoftMM.Max},
+		} {
+			if significant(v.a, v.b, opts.Value) {
+				seq = append(seq, types.Delta{Value: v.value, From: v.a, To: v.b})
+			}
+		}
+	}
+
+	if len(seq) == 0 {
+		return nil
+	}
+
+	return seq
+}
+
+// relative change of value is significant
+func significant(a, b, threshold float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) || a == b {
+		return false
+	}
+
+	return math.Abs(b-a)/math.Max(math.Abs(a), math.Abs(b)) >= threshold
+}
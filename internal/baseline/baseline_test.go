@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package baseline_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/baseline"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+const (
+	pass = types.STATUS_CODE_SUCCESS
+	warn = types.STATUS_CODE_WARNING
+	fail = types.STATUS_CODE_FAILURE
+)
+
+func status(code types.StatusCode, rate, avg float64) types.Status {
+	return types.Status{
+		Code:        code,
+		Rule:        types.Rule{ID: "D3", Unit: "ms", About: "storage i/o latency"},
+		Interval:    5 * time.Minute,
+		SuccessRate: &rate,
+		SoftMM:      &types.MinMax{Min: 1.0, Avg: avg, Max: math.NaN()},
+	}
+}
+
+func node(name string, s types.Status) types.StatusNode {
+	return types.StatusNode{Status: s.Code, Node: &types.Node{Name: name}, Checks: []types.Status{s}}
+}
+
+func expect(t *testing.T, seq []types.Change, expected ...string) {
+	t.Helper()
+
+	if len(seq) != len(expected) {
+		t.Fatalf("unexpected changes |%+v|", seq)
+	}
+
+	for i, x := range seq {
+		id := ""
+		if x.Rule != nil {
+			id = " " + x.Rule.ID
+		}
+
+		v := string(x.Kind) + " " + x.Node + id + " " + x.From.String() + " " + x.To.String()
+		if v != expected[i] {
+			t.Errorf("unexpected change %s, expected %s", v, expected[i])
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{Cluster: &types.Cluster{ID: "c"}, Writer: []types.StatusNode{node("a", status(pass, 100.0, 2.0))}},
+		},
+		Nodes: []types.StatusNode{
+			node("b", status(fail, 60.0, 20.0)),
+			node("c", status(warn, 90.0, 8.0)),
+			node("d", status(pass, 100.0, 2.0)),
+		},
+	}
+
+	now := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{Cluster: &types.Cluster{ID: "c"}, Writer: []types.StatusNode{node("a", status(fail, 70.0, 12.0))}},
+		},
+		Nodes: []types.StatusNode{
+			node("b", status(pass, 100.0, 3.0)),
+			node("c", status(warn, 91.0, 8.5)),
+			node("e", status(pass, 100.0, 2.0)),
+		},
+	}
+
+	seq := baseline.Compare(old, now, baseline.Default)
+	expect(t, seq,
+		"failure a D3 PASSED FAILED",
+		"recovery b D3 FAILED PASSED",
+		"added e UNKNOWN PASSED",
+		"removed d PASSED UNKNOWN",
+	)
+
+	if seq[0].Cluster != "c" || len(seq[0].Deltas) != 2 {
+		t.Errorf("unexpected deltas |%+v|", seq[0])
+	}
+
+	// significant changes of values only
+	now.Nodes[1] = node("c", status(warn, 80.0, 8.0))
+	expect(t, baseline.Compare(old, now, baseline.Default)[2:3],
+		"changed c D3 WARNED WARNED",
+	)
+
+	expect(t, baseline.Compare(old, old, baseline.Default))
+}
+
+func TestCompareCluster(t *testing.T) {
+	cluster := func(code types.StatusCode) types.StatusRegion {
+		observed := "eu-central-1a"
+		return types.StatusRegion{
+			Clusters: []types.StatusCluster{
+				{
+					Cluster: &types.Cluster{ID: "c"},
+					Writer:  []types.StatusNode{node("a", status(pass, 100.0, 2.0))},
+					Checks:  []types.Status{{Code: code, Rule: types.Rule{ID: "R4", About: "members in single zone"}, Observed: &observed}},
+				},
+			},
+		}
+	}
+
+	seq := baseline.Compare(cluster(pass), cluster(fail), baseline.Default)
+	expect(t, seq,
+		"failure c R4 PASSED FAILED",
+	)
+
+	if seq[0].Cluster != "c" {
+		t.Errorf("unexpected cluster of change |%+v|", seq[0])
+	}
+
+	expect(t, baseline.Compare(cluster(fail), cluster(pass), baseline.Default),
+		"recovery c R4 FAILED PASSED",
+	)
+}
+
+func TestCompareNode(t *testing.T) {
+	old := types.StatusRegion{
+		Nodes: []types.StatusNode{
+			node("a", status(pass, 100.0, 2.0)),
+			node("b", status(fail, 60.0, 20.0)),
+		},
+	}
+
+	expect(t, baseline.CompareNode(old, node("b", status(warn, 80.0, 10.0)), baseline.Default),
+		"recovery b D3 FAILED WARNED",
+	)
+
+	expect(t, baseline.CompareNode(old, node("e", status(pass, 100.0, 2.0)), baseline.Default),
+		"added e UNKNOWN PASSED",
+	)
+}
+
+func TestRead(t *testing.T) {
+	n := node("a", status(fail, 60.0, 20.0))
+
+	for _, v := range []any{n, types.StatusRegion{Status: fail, Nodes: []types.StatusNode{n}}} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+
+		region, err := baseline.Read(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+
+		if region.Status != fail || len(region.Nodes) != 1 {
+			t.Fatalf("unexpected snapshot |%+v|", region)
+		}
+
+		s := region.Nodes[0].Checks[0]
+		switch {
+		case s.Rule.ID != "D3" || s.Interval != 5*time.Minute:
+			t.Errorf("unexpected status |%+v|", s)
+		case *s.SuccessRate != 60.0 || s.SoftMM.Avg != 20.0 || !math.IsNaN(s.SoftMM.Max):
+			t.Errorf("unexpected values |%+v|", s.SoftMM)
+		}
+	}
+
+	if _, err := baseline.Read(bytes.NewReader([]byte("{"))); err == nil {
+		t.Errorf("should fail for invalid snapshot")
+	}
+}
@@ -8,36 +8,155 @@
 
 package cache
 
-import "context"
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is the negative result, it is cached like any other value.
+// Other errors are returned to the caller but never cached.
+var ErrNotFound = errors.New("not found")
+
+// NotFound marks the error as negative result, the original error is kept
+// so that it is matched by errors.As.
+func NotFound(err error) error { return notFound{err: err} }
+
+type notFound struct{ err error }
+
+func (e notFound) Error() string        { return e.err.Error() }
+func (e notFound) Unwrap() error        { return e.err }
+func (e notFound) Is(target error) bool { return target == ErrNotFound }
 
 type Getter[K comparable, V any] interface {
 	Lookup(context.Context, K) (V, error)
 }
 
+// Option of the cache
+type Option func(*options)
+
+type options struct {
+	ttl       time.Duration
+	disk      *Disk
+	namespace string
+}
+
+// WithTTL defines time-to-live of cached values
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) { o.ttl = ttl }
+}
+
+// WithDisk persists cached values to the directory, the namespace
+// isolates values of different caches sharing the directory.
+func WithDisk(disk *Disk, namespace string) Option {
+	return func(o *options) {
+		o.disk = disk
+		o.namespace = namespace
+	}
+}
+
+type entry[V any] struct {
+	Value   V         `json:"value"`
+	Missing string    `json:"missing,omitempty"`
+	Expires time.Time `json:"expires"`
+
+	// the original negative result, only the message is persisted on disk
+	err error
+}
+
+func (e entry[V]) result() (V, error) {
+	switch {
+	case e.err != nil:
+		return e.Value, e.err
+	case e.Missing != "":
+		return e.Value, NotFound(errors.New(e.Missing))
+	}
+
+	return e.Value, nil
+}
+
+// Cache is concurrency-safe read-through cache with time-to-live
 type Cache[K comparable, V any] struct {
-	keyval map[K]V
+	options
+	mu     sync.Mutex
+	keyval map[K]entry[V]
 	getter Getter[K, V]
 }
 
-func New[K comparable, V any](getter Getter[K, V]) *Cache[K, V] {
-	return &Cache[K, V]{
-		keyval: make(map[K]V),
-		getter: getter,
+func New[K comparable, V any](getter Getter[K, V], opts ...Option) *Cache[K, V] {
+	c := &Cache[K, V]{
+		options: options{ttl: 15 * time.Minute},
+		keyval:  make(map[K]entry[V]),
+		getter:  getter,
 	}
 
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+
+	// cache is best effort, expired values are left if the sweep fails
+	if c.disk != nil {
+		c.disk.sweep(c.namespace)
+	}
+
+	return c
+}
+
+// Lookup value using the getter of the cache
+func (c *Cache[K, V]) Lookup(ctx context.Context, key K) (V, error) {
+	return c.Get(ctx, key, func(ctx context.Context) (V, error) {
+		return c.getter.Lookup(ctx, key)
+	})
+}
+
+// Get cached value or fetch it if the value is missing or expired
+func (c *Cache[K, V]) Get(ctx context.Context, key K, fetch func(context.Context) (V, error)) (V, error) {
+	now := time.Now()
+
+	if e, has := c.read(key); has && now.Before(e.Expires) {
+		return e.result()
+	}
+
+	val, err := fetch(ctx)
+	switch {
+	case err == nil:
+		c.write(key, entry[V]{Value: val, Expires: now.Add(c.ttl)})
+	case errors.Is(err, ErrNotFound):
+		c.write(key, entry[V]{Missing: err.Error(), Expires: now.Add(c.ttl), err: err})
+	}
+
+	return val, err
 }
 
-func (c Cache[K, V]) Lookup(ctx context.Context, key K) (V, error) {
-	if v, has := c.keyval[key]; has {
-		return v, nil
+func (c *Cache[K, V]) read(key K) (entry[V], bool) {
+	c.mu.Lock()
+	e, has := c.keyval[key]
+	c.mu.Unlock()
+
+	if has || c.disk == nil {
+		return e, has
 	}
 
-	v, err := c.getter.Lookup(ctx, key)
-	if err != nil {
-		return *new(V), nil
+	// corrupted or incompatible files are treated as cache miss
+	if has, err := c.disk.read(c.namespace, key, &e); err != nil || !has {
+		return e, false
 	}
 
-	c.keyval[key] = v
+	c.mu.Lock()
+	c.keyval[key] = e
+	c.mu.Unlock()
 
-	return v, nil
+	return e, true
+}
+
+func (c *Cache[K, V]) write(key K, e entry[V]) {
+	c.mu.Lock()
+	c.keyval[key] = e
+	c.mu.Unlock()
+
+	// cache is best effort, failure to persist does not fail the lookup
+	if c.disk != nil {
+		c.disk.write(c.namespace, key, e)
+	}
 }
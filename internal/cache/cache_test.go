@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/cache"
+)
+
+type getter struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (g *getter) Lookup(ctx context.Context, key string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.calls++
+	if g.err != nil {
+		return "", g.err
+	}
+	return "value of " + key, nil
+}
+
+func TestLookup(t *testing.T) {
+	g := &getter{}
+	sut := cache.New[string, string](g)
+
+	for i := 0; i < 3; i++ {
+		val, err := sut.Lookup(context.TODO(), "a")
+		switch {
+		case err != nil:
+			t.Errorf("should not fail with error %s", err)
+		case val != "value of a":
+			t.Errorf("should not return unexpected value |%s|", val)
+		}
+	}
+
+	if g.calls != 1 {
+		t.Errorf("should fetch value once |%d|", g.calls)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	g := &getter{}
+	sut := cache.New[string, string](g, cache.WithTTL(10*time.Millisecond))
+
+	sut.Lookup(context.TODO(), "a")
+	time.Sleep(20 * time.Millisecond)
+	sut.Lookup(context.TODO(), "a")
+
+	if g.calls != 2 {
+		t.Errorf("should fetch expired value |%d|", g.calls)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	g := &getter{err: errors.New("failed")}
+	sut := cache.New[string, string](g)
+
+	for i := 0; i < 2; i++ {
+		if _, err := sut.Lookup(context.TODO(), "a"); err == nil {
+			t.Errorf("should propagate error")
+		}
+	}
+
+	if g.calls != 2 {
+		t.Errorf("should not cache errors |%d|", g.calls)
+	}
+}
+
+func TestNegative(t *testing.T) {
+	g := &getter{err: cache.NotFound(fmt.Errorf("rds a is not found"))}
+	sut := cache.New[string, string](g)
+
+	for i := 0; i < 2; i++ {
+		_, err := sut.Lookup(context.TODO(), "a")
+		switch {
+		case !errors.Is(err, cache.ErrNotFound):
+			t.Errorf("should return negative result |%v|", err)
+		case err.Error() != "rds a is not found":
+			t.Errorf("should keep error message |%s|", err)
+		}
+	}
+
+	if g.calls != 1 {
+		t.Errorf("should cache negative result |%d|", g.calls)
+	}
+}
+
+type faultError struct{ id string }
+
+func (e *faultError) Error() string { return "fault " + e.id }
+
+func TestNegativeKeepsError(t *testing.T) {
+	g := &getter{err: cache.NotFound(&faultError{id: "a"})}
+	sut := cache.New[string, string](g)
+
+	for i := 0; i < 2; i++ {
+		_, err := sut.Lookup(context.TODO(), "a")
+
+		var fault *faultError
+		switch {
+		case !errors.Is(err, cache.ErrNotFound):
+			t.Errorf("should return negative result |%v|", err)
+		case !errors.As(err, &fault) || fault.id != "a":
+			t.Errorf("should keep the original error |%v|", err)
+		}
+	}
+}
+
+func TestDisk(t *testing.T) {
+	disk, err := cache.NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	g := &getter{}
+	a := cache.New[string, string](g, cache.WithDisk(disk, "test"))
+	a.Lookup(context.TODO(), "a")
+
+	// another process shares the directory
+	b := cache.New[string, string](g, cache.WithDisk(disk, "test"))
+	val, err := b.Lookup(context.TODO(), "a")
+	switch {
+	case err != nil:
+		t.Errorf("should not fail with error %s", err)
+	case val != "value of a":
+		t.Errorf("should not return unexpected value |%s|", val)
+	case g.calls != 1:
+		t.Errorf("should read value from disk |%d|", g.calls)
+	}
+
+	// namespaces are isolated
+	c := cache.New[string, string](g, cache.WithDisk(disk, "other"))
+	c.Lookup(context.TODO(), "a")
+	if g.calls != 2 {
+		t.Errorf("should isolate namespaces |%d|", g.calls)
+	}
+}
+
+func TestDiskEviction(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := cache.NewDisk(dir)
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	files := func() int {
+		seq, _ := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+		return len(seq)
+	}
+
+	g := &getter{}
+	c := cache.New[string, string](g, cache.WithDisk(disk, "test"))
+
+	a := cache.New[string, string](g, cache.WithTTL(-time.Second), cache.WithDisk(disk, "test"))
+	a.Lookup(context.TODO(), "a")
+	a.Lookup(context.TODO(), "b")
+
+	b := cache.New[string, string](g, cache.WithDisk(disk, "other"))
+	b.Lookup(context.TODO(), "a")
+
+	if files() != 3 {
+		t.Fatalf("should persist values |%d|", files())
+	}
+
+	// expired values are deleted on read
+	if _, err := c.Get(context.TODO(), "b", func(context.Context) (string, error) { return "", errors.New("fails") }); err == nil {
+		t.Errorf("should not read expired value")
+	}
+	if files() != 2 {
+		t.Errorf("should delete expired value on read |%d|", files())
+	}
+
+	// expired values of the namespace are deleted on open
+	cache.New[string, string](g, cache.WithDisk(disk, "test"))
+	if files() != 1 {
+		t.Errorf("should delete expired values of the namespace |%d|", files())
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	g := &getter{}
+	sut := cache.New[string, string](g)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("%d", i%10)
+			if val, _ := sut.Lookup(context.TODO(), key); val != "value of "+key {
+				t.Errorf("should not return unexpected value |%s|", val)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
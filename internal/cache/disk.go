@@ -0,0 +1,165 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Disk is the directory of cached values, one JSON file per value and one
+// sub-directory per namespace. Expired values are deleted when they are
+// read and when the namespace is opened.
+type Disk struct {
+	dir string
+}
+
+// DefaultDir is the user's cache directory (e.g. ~/.cache/rds-health)
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "rds-health"), nil
+}
+
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &Disk{dir: dir}, nil
+}
+
+// Clear all cached values
+func (d *Disk) Clear() error {
+	return os.RemoveAll(d.dir)
+}
+
+// expiry of the cached value, it is persisted along with the value
+type expiry struct {
+	Expires time.Time `json:"expires"`
+}
+
+func (d *Disk) space(namespace string) string {
+	hash := sha256.Sum256([]byte(namespace))
+	return filepath.Join(d.dir, hex.EncodeToString(hash[:16]))
+}
+
+func (d *Disk) path(namespace string, key any) (string, error) {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(b)
+	return filepath.Join(d.space(namespace), hex.EncodeToString(hash[:])+".json"), nil
+}
+
+func (d *Disk) read(namespace string, key any, val any) (bool, error) {
+	path, err := d.path(namespace, key)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	var exp expiry
+	if err := json.Unmarshal(b, &exp); err != nil || !time.Now().Before(exp.Expires) {
+		os.Remove(path)
+		return false, err
+	}
+
+	if err := json.Unmarshal(b, val); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// deletes expired and corrupted values of the namespace
+func (d *Disk) sweep(namespace string) error {
+	dir := d.space(namespace)
+
+	files, err := os.ReadDir(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var exp expiry
+		if err := json.Unmarshal(b, &exp); err != nil || !now.Before(exp.Expires) {
+			os.Remove(path)
+		}
+	}
+
+	return nil
+}
+
+func (d *Disk) write(namespace string, key any, val any) error {
+	path, err := d.path(namespace, key)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// concurrent processes never observe partially written files
+	f, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
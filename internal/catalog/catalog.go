@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package defines catalog of RDS instance classes and its hardware specs.
+// The catalog is embedded into the application (see classes.json), it is
+// regenerated from AWS APIs using `go generate ./internal/catalog`.
+//
+
+//go:generate go run ./generate -o classes.json
+
+//go:embed classes.json
+var embedded []byte
+
+// RDS instance class (e.g. db.m6g.large)
+type Class struct {
+	Class   string  `json:"class"`
+	VCPU    int     `json:"vcpu"`
+	Memory  float64 `json:"memory"`  // GiB
+	Arch    string  `json:"arch"`    // x86_64 or arm64
+	Network float64 `json:"network"` // peak network bandwidth, Gbps
+	EBS     float64 `json:"ebs"`     // maximum EBS bandwidth, Mbps
+}
+
+// Family of the class (e.g. m6g for db.m6g.large)
+func (c Class) Family() string {
+	seq := strings.Split(c.Class, ".")
+	if len(seq) < 3 {
+		return ""
+	}
+	return seq[1]
+}
+
+// Series of the class (e.g. m for db.m6g.large)
+func (c Class) Series() string {
+	family := c.Family()
+	if family == "" {
+		return ""
+	}
+	return family[:1]
+}
+
+// Generation of the class (e.g. 6 for db.m6g.large)
+func (c Class) Generation() int {
+	for _, r := range c.Family() {
+		if r >= '0' && r <= '9' {
+			return int(r - '0')
+		}
+	}
+	return 0
+}
+
+// Burstable classes accumulate cpu credits, its capacity is not stable
+func (c Class) Burstable() bool { return c.Series() == "t" }
+
+// Graviton classes are based on AWS arm64 processors
+func (c Class) Graviton() bool { return c.Arch == "arm64" }
+
+// Compute resources of the class
+func (c Class) Compute() *types.Compute {
+	return &types.Compute{
+		CPU:     &types.CPU{Cores: c.VCPU},
+		Memory:  &types.Storage{Type: "memory", Size: types.BiB(c.Memory * float64(types.GiB))},
+		Network: types.Mbps(c.Network * 1000),
+		EBS:     types.Mbps(c.EBS),
+	}
+}
+
+// Catalog of instance classes
+type Catalog struct {
+	Updated string  `json:"updated"`
+	Classes []Class `json:"classes"`
+}
+
+// Default catalog used by the application
+var Default = must(Decode(embedded))
+
+func must(c *Catalog, err error) *Catalog {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Decode catalog from JSON
+func Decode(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Lookup the instance class
+func (c *Catalog) Lookup(class string) (*Class, bool) {
+	for i := range c.Classes {
+		if c.Classes[i].Class == class {
+			return &c.Classes[i], true
+		}
+	}
+
+	return nil, false
+}
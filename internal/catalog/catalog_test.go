@@ -0,0 +1,36 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/zalando/rds-health/internal/catalog"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestLookup(t *testing.T) {
+	class, has := catalog.Default.Lookup("db.m6g.large")
+	switch {
+	case !has:
+		t.Errorf("should find class")
+	case class.VCPU != 2 || class.Memory != 8:
+		t.Errorf("should not return unexpected spec |%+v|", class)
+	case class.Family() != "m6g" || class.Series() != "m" || class.Generation() != 6:
+		t.Errorf("should not return unexpected family |%s|", class.Family())
+	case !class.Graviton() || class.Burstable():
+		t.Errorf("should be graviton, non-burstable class")
+	case class.Compute().Memory.Size != 8*types.GiB:
+		t.Errorf("should not return unexpected compute |%s|", class.Compute())
+	}
+
+	if _, has := catalog.Default.Lookup("db.m6g.huge"); has {
+		t.Errorf("should not find unknown class")
+	}
+}
@@ -0,0 +1,203 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+// Generates catalog of RDS instance classes. It requires AWS credentials
+// with permissions rds:DescribeOrderableDBInstanceOptions and
+// ec2:DescribeInstanceTypes.
+//
+//	go generate ./internal/catalog
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/zalando/rds-health/internal/catalog"
+)
+
+var (
+	output   = flag.String("o", "classes.json", "output file")
+	engines  = flag.String("engines", "postgres,aurora-postgresql,mysql,aurora-mysql", "comma separated list of engines")
+	families = flag.String("families", "m5,m6i,m7i,m6g,m7g,r5,r6i,r7i,r6g,r7g,x2g,t3,t4g", "comma separated list of families in the order of catalog")
+)
+
+func main() {
+	flag.Parse()
+
+	if err := generate(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(ctx context.Context) error {
+	conf, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	classes, err := orderable(ctx, rds.NewFromConfig(conf), strings.Split(*engines, ","), strings.Split(*families, ","))
+	if err != nil {
+		return err
+	}
+
+	seq, err := specs(ctx, ec2.NewFromConfig(conf), classes, strings.Split(*families, ","))
+	if err != nil {
+		return err
+	}
+
+	return write(catalog.Catalog{
+		Updated: time.Now().Format(time.DateOnly),
+		Classes: seq,
+	})
+}
+
+// orderable instance classes of engines and families, RDS only classes are
+// excluded
+func orderable(ctx context.Context, api *rds.Client, engines, families []string) ([]string, error) {
+	classes := make([]string, 0)
+
+	for _, engine := range engines {
+		var cursor *string
+		for do := true; do; do = cursor != nil {
+			bag, err := api.DescribeOrderableDBInstanceOptions(ctx,
+				&rds.DescribeOrderableDBInstanceOptionsInput{
+					Engine:     aws.String(engine),
+					Marker:     cursor,
+					MaxRecords: aws.Int32(1000),
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, opt := range bag.OrderableDBInstanceOptions {
+				class := aws.ToString(opt.DBInstanceClass)
+				family := catalog.Class{Class: class}.Family()
+				if strings.Count(class, ".") == 2 && slices.Contains(families, family) && !slices.Contains(classes, class) {
+					classes = append(classes, class)
+				}
+			}
+			cursor = bag.Marker
+		}
+	}
+
+	return classes, nil
+}
+
+// specs of instance classes using equivalent EC2 instance types, ordered
+// by family then by capacity
+func specs(ctx context.Context, api *ec2.Client, classes, families []string) ([]catalog.Class, error) {
+	seq := make([]catalog.Class, 0, len(classes))
+
+	for i := 0; i < len(classes); i += 100 {
+		batch := make([]ec2types.InstanceType, 0, 100)
+		for _, class := range classes[i:min(i+100, len(classes))] {
+			batch = append(batch, ec2types.InstanceType(strings.TrimPrefix(class, "db.")))
+		}
+
+		var cursor *string
+		for do := true; do; do = cursor != nil {
+			bag, err := api.DescribeInstanceTypes(ctx,
+				&ec2.DescribeInstanceTypesInput{
+					InstanceTypes: batch,
+					NextToken:     cursor,
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, it := range bag.InstanceTypes {
+				seq = append(seq, toClass(it))
+			}
+			cursor = bag.NextToken
+		}
+	}
+
+	slices.SortFunc(seq, func(a, b catalog.Class) int {
+		return cmp.Or(
+			slices.Index(families, a.Family())-slices.Index(families, b.Family()),
+			a.VCPU-b.VCPU,
+			cmp.Compare(a.Memory, b.Memory),
+		)
+	})
+
+	return seq, nil
+}
+
+func toClass(it ec2types.InstanceTypeInfo) catalog.Class {
+	class := catalog.Class{Class: "db." + string(it.InstanceType)}
+
+	if cpu := it.VCpuInfo; cpu != nil {
+		class.VCPU = int(aws.ToInt32(cpu.DefaultVCpus))
+	}
+
+	if mem := it.MemoryInfo; mem != nil {
+		class.Memory = float64(aws.ToInt64(mem.SizeInMiB)) / 1024
+	}
+
+	// types might support i386 along with x86_64, RDS runs on 64 bit only
+	if proc := it.ProcessorInfo; proc != nil {
+		for _, arch := range proc.SupportedArchitectures {
+			if arch == ec2types.ArchitectureTypeX8664 || arch == ec2types.ArchitectureTypeArm64 {
+				class.Arch = string(arch)
+				break
+			}
+		}
+	}
+
+	if net := it.NetworkInfo; net != nil && len(net.NetworkCards) != 0 {
+		class.Network = aws.ToFloat64(net.NetworkCards[0].PeakBandwidthInGbps)
+	}
+
+	if ebs := it.EbsInfo; ebs != nil && ebs.EbsOptimizedInfo != nil {
+		class.EBS = float64(aws.ToInt32(ebs.EbsOptimizedInfo.MaximumBandwidthInMbps))
+	}
+
+	return class
+}
+
+// one class per line keeps the diff of regenerated catalog readable
+func write(c catalog.Catalog) error {
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "{\n  \"updated\": %q,\n  \"classes\": [\n", c.Updated)
+	for i, class := range c.Classes {
+		sep := ","
+		if i == len(c.Classes)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(f, "    {\"class\": %q, \"vcpu\": %d, \"memory\": %s, \"arch\": %q, \"network\": %s, \"ebs\": %s}%s\n",
+			class.Class, class.VCPU, num(class.Memory), class.Arch, num(class.Network), num(class.EBS), sep)
+	}
+	fmt.Fprintf(f, "  ]\n}\n")
+
+	return f.Close()
+}
+
+// shortest representation of the number (e.g. 10, 12.5)
+func num(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
@@ -34,14 +34,16 @@ func New(provider Provider) *Cluster {
 	return &Cluster{provider: provider}
 }
 
-func (api Cluster) Lookup(ctx context.Context) ([]types.Cluster, error) {
+// Lookup all database clusters, optionally narrowed by server-side filters
+func (api Cluster) Lookup(ctx context.Context, filters ...rdstypes.Filter) ([]types.Cluster, error) {
 	clusters := make([]types.Cluster, 0)
 
 	var cursor *string
 	for do := true; do; do = cursor != nil {
 		bag, err := api.provider.DescribeDBClusters(ctx,
 			&rds.DescribeDBClustersInput{
-				Marker: cursor,
+				Marker:  cursor,
+				Filters: filters,
 			},
 		)
 		if err != nil {
@@ -60,6 +62,7 @@ func (api Cluster) Lookup(ctx context.Context) ([]types.Cluster, error) {
 func (api Cluster) toCluster(c rdstypes.DBCluster) types.Cluster {
 	cluster := types.Cluster{
 		ID:     aws.ToString(c.DBClusterIdentifier),
+		ARN:    aws.ToString(c.DBClusterArn),
 		Reader: make([]types.Node, 0),
 		Writer: make([]types.Node, 0),
 	}
@@ -71,6 +74,19 @@ func (api Cluster) toCluster(c rdstypes.DBCluster) types.Cluster {
 		}
 	}
 
+	cluster.Config = &types.ClusterConfig{
+		Encrypted:          aws.ToBool(c.StorageEncrypted),
+		BackupRetention:    int(aws.ToInt32(c.BackupRetentionPeriod)),
+		DeletionProtection: aws.ToBool(c.DeletionProtection),
+	}
+
+	if len(c.TagList) != 0 {
+		cluster.Tags = make(map[string]string, len(c.TagList))
+		for _, tag := range c.TagList {
+			cluster.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
 	for _, member := range c.DBClusterMembers {
 		node := types.Node{
 			Name: aws.ToString(member.DBInstanceIdentifier),
@@ -27,9 +27,12 @@ func TestLookup(t *testing.T) {
 	fix := &rds.DescribeDBClustersOutput{
 		DBClusters: []rdstypes.DBCluster{
 			{
-				DBClusterIdentifier: aws.String("test-db"),
-				Engine:              aws.String("postgres"),
-				EngineVersion:       aws.String("13.14"),
+				DBClusterIdentifier:   aws.String("test-db"),
+				Engine:                aws.String("postgres"),
+				EngineVersion:         aws.String("13.14"),
+				StorageEncrypted:      aws.Bool(true),
+				BackupRetentionPeriod: aws.Int32(7),
+				DeletionProtection:    aws.Bool(true),
 				DBClusterMembers: []rdstypes.DBClusterMember{
 					{
 						DBInstanceIdentifier: aws.String("test-1"),
@@ -56,10 +59,12 @@ func TestLookup(t *testing.T) {
 	case len(seq) == 0:
 		t.Errorf("should return db instances")
 	case seq[0].ID != "test-db":
-		t.Errorf("should not return unexpected value |%s|", seq[0])
+		t.Errorf("should not return unexpected value |%v|", seq[0])
 	case len(seq[0].Writer) == 0:
 		t.Errorf("should have writer nodes")
 	case len(seq[0].Reader) == 0:
 		t.Errorf("should have reader nodes")
+	case seq[0].Config == nil || !seq[0].Config.Encrypted || seq[0].Config.BackupRetention != 7 || !seq[0].Config.DeletionProtection:
+		t.Errorf("should have cluster config |%+v|", seq[0].Config)
 	}
 }
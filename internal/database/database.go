@@ -10,11 +10,13 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/zalando/rds-health/internal/cache"
 	"github.com/zalando/rds-health/internal/types"
 )
 
@@ -25,8 +27,19 @@ type Provider interface {
 		*rds.DescribeDBInstancesInput,
 		...func(*rds.Options),
 	) (*rds.DescribeDBInstancesOutput, error)
+
+	DescribePendingMaintenanceActions(
+		context.Context,
+		*rds.DescribePendingMaintenanceActionsInput,
+		...func(*rds.Options),
+	) (*rds.DescribePendingMaintenanceActionsOutput, error)
 }
 
+// ErrNotFound is returned if the database instance does not exist. It is
+// shared with negative results of the cache so that the cached "not found"
+// faults are matched too.
+var ErrNotFound = cache.ErrNotFound
+
 type Database struct {
 	provider Provider
 }
@@ -35,14 +48,16 @@ func New(provider Provider) *Database {
 	return &Database{provider: provider}
 }
 
-func (db *Database) LookupAll(ctx context.Context) ([]types.Node, error) {
+// Lookup all database instances, optionally narrowed by server-side filters
+func (db *Database) LookupAll(ctx context.Context, filters ...rdstypes.Filter) ([]types.Node, error) {
 	clusters := make([]types.Node, 0)
 
 	var cursor *string
 	for do := true; do; do = cursor != nil {
 		bag, err := db.provider.DescribeDBInstances(ctx,
 			&rds.DescribeDBInstancesInput{
-				Marker: cursor,
+				Marker:  cursor,
+				Filters: filters,
 			},
 		)
 		if err != nil {
@@ -63,18 +78,54 @@ func (db *Database) Lookup(ctx context.Context, name string) (*types.Node, error
 	val, err := db.provider.DescribeDBInstances(ctx,
 		&rds.DescribeDBInstancesInput{DBInstanceIdentifier: &name},
 	)
+	var notFound *rdstypes.DBInstanceNotFoundFault
+	if errors.As(err, &notFound) || errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w: rds %s", ErrNotFound, name)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	if len(val.DBInstances) == 0 {
-		return nil, fmt.Errorf("not found: rds %s", name)
+		return nil, fmt.Errorf("%w: rds %s", ErrNotFound, name)
 	}
 
 	node := db.toNode(val.DBInstances[0])
 	return &node, nil
 }
 
+// Lookup pending maintenance actions of instances and clusters, indexed by ARN
+func (db *Database) LookupMaintenance(ctx context.Context) (map[string][]types.Maintenance, error) {
+	actions := make(map[string][]types.Maintenance)
+
+	var cursor *string
+	for do := true; do; do = cursor != nil {
+		bag, err := db.provider.DescribePendingMaintenanceActions(ctx,
+			&rds.DescribePendingMaintenanceActionsInput{
+				Marker: cursor,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range bag.PendingMaintenanceActions {
+			arn := aws.ToString(r.ResourceIdentifier)
+			for _, a := range r.PendingMaintenanceActionDetails {
+				actions[arn] = append(actions[arn], types.Maintenance{
+					Action:      aws.ToString(a.Action),
+					Description: aws.ToString(a.Description),
+					AutoApply:   a.AutoAppliedAfterDate,
+					ForcedApply: a.ForcedApplyDate,
+				})
+			}
+		}
+		cursor = bag.Marker
+	}
+
+	return actions, nil
+}
+
 func (db *Database) toNode(instance rdstypes.DBInstance) types.Node {
 	engine := types.Engine{
 		ID:      aws.ToString(instance.Engine),
@@ -95,15 +146,44 @@ func (db *Database) toNode(instance rdstypes.DBInstance) types.Node {
 		az = append(az, aws.ToString(instance.SecondaryAvailabilityZone))
 	}
 
-	// instance.DbiResourceId
+	var tags map[string]string
+	if len(instance.TagList) != 0 {
+		tags = make(map[string]string, len(instance.TagList))
+		for _, tag := range instance.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	config := types.Config{
+		Status:             aws.ToString(instance.DBInstanceStatus),
+		MultiAZ:            aws.ToBool(instance.MultiAZ),
+		IOPS:               int(aws.ToInt32(instance.Iops)),
+		Throughput:         int(aws.ToInt32(instance.StorageThroughput)),
+		MaxStorage:         types.BiB(aws.ToInt32(instance.MaxAllocatedStorage)) * types.GiB,
+		Encrypted:          aws.ToBool(instance.StorageEncrypted),
+		BackupRetention:    int(aws.ToInt32(instance.BackupRetentionPeriod)),
+		Insights:           aws.ToBool(instance.PerformanceInsightsEnabled),
+		InsightsRetention:  int(aws.ToInt32(instance.PerformanceInsightsRetentionPeriod)),
+		DeletionProtection: aws.ToBool(instance.DeletionProtection),
+		PubliclyAccessible: aws.ToBool(instance.PubliclyAccessible),
+		CACertificate:      aws.ToString(instance.CACertificateIdentifier),
+	}
+
+	if len(instance.DBParameterGroups) != 0 {
+		config.ParameterGroup = aws.ToString(instance.DBParameterGroups[0].DBParameterGroupName)
+	}
 
 	node := types.Node{
 		ID:      aws.ToString(instance.DbiResourceId),
+		ARN:     aws.ToString(instance.DBInstanceArn),
 		Name:    aws.ToString(instance.DBInstanceIdentifier),
 		Type:    aws.ToString(instance.DBInstanceClass),
 		Zones:   az,
 		Engine:  &engine,
 		Storage: &storage,
+		Cluster: aws.ToString(instance.DBClusterIdentifier),
+		Config:  &config,
+		Tags:    tags,
 	}
 
 	return node
@@ -10,6 +10,7 @@ package database_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -17,6 +18,7 @@ import (
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/zalando/rds-health/internal/database"
 	"github.com/zalando/rds-health/internal/mocks"
+	"github.com/zalando/rds-health/internal/types"
 	"go.uber.org/mock/gomock"
 )
 
@@ -89,3 +91,95 @@ func TestLookupSuccess(t *testing.T) {
 		t.Errorf("should not return unexpected value |%s|", db)
 	}
 }
+
+func TestLookupNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mocks.NewDatabase(ctrl)
+	mock.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Return(nil, &rdstypes.DBInstanceNotFoundFault{})
+	mock.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Return(&rds.DescribeDBInstancesOutput{}, nil)
+
+	sut := database.New(mock)
+
+	for i := 0; i < 2; i++ {
+		if _, err := sut.Lookup(context.TODO(), "test-db"); !errors.Is(err, database.ErrNotFound) {
+			t.Errorf("should fail with not found error |%v|", err)
+		}
+	}
+}
+
+func TestLookupConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fix := &rds.DescribeDBInstancesOutput{
+		DBInstances: []rdstypes.DBInstance{
+			{
+				DBInstanceIdentifier:       aws.String("test-db"),
+				DBInstanceClass:            aws.String("db.t2.small"),
+				DBInstanceStatus:           aws.String("available"),
+				MultiAZ:                    aws.Bool(true),
+				Iops:                       aws.Int32(3000),
+				MaxAllocatedStorage:        aws.Int32(1000),
+				BackupRetentionPeriod:      aws.Int32(7),
+				PerformanceInsightsEnabled: aws.Bool(true),
+				DBParameterGroups: []rdstypes.DBParameterGroupStatus{
+					{DBParameterGroupName: aws.String("default.postgres13")},
+				},
+			},
+		},
+	}
+
+	mock := mocks.NewDatabase(ctrl)
+	mock.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Return(fix, nil)
+
+	sut := database.New(mock)
+
+	db, err := sut.Lookup(context.TODO(), "test-db")
+	switch {
+	case err != nil:
+		t.Errorf("should not failed with error %s", err)
+	case db.Config == nil:
+		t.Errorf("should return config")
+	case db.Config.Status != "available" || !db.Config.MultiAZ || !db.Config.Insights:
+		t.Errorf("should not return unexpected config |%+v|", db.Config)
+	case db.Config.IOPS != 3000 || db.Config.MaxStorage != 1000*types.GiB || db.Config.BackupRetention != 7:
+		t.Errorf("should not return unexpected storage config |%+v|", db.Config)
+	case db.Config.ParameterGroup != "default.postgres13":
+		t.Errorf("should not return unexpected parameter group |%s|", db.Config.ParameterGroup)
+	}
+}
+
+func TestLookupMaintenance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fix := &rds.DescribePendingMaintenanceActionsOutput{
+		PendingMaintenanceActions: []rdstypes.ResourcePendingMaintenanceActions{
+			{
+				ResourceIdentifier: aws.String("arn:aws:rds:eu-central-1:000000000000:db:test-db"),
+				PendingMaintenanceActionDetails: []rdstypes.PendingMaintenanceAction{
+					{Action: aws.String("system-update")},
+					{Action: aws.String("db-upgrade")},
+				},
+			},
+		},
+	}
+
+	mock := mocks.NewDatabase(ctrl)
+	mock.EXPECT().DescribePendingMaintenanceActions(gomock.Any(), gomock.Any()).Return(fix, nil)
+
+	sut := database.New(mock)
+
+	actions, err := sut.LookupMaintenance(context.TODO())
+	seq := actions["arn:aws:rds:eu-central-1:000000000000:db:test-db"]
+	switch {
+	case err != nil:
+		t.Errorf("should not failed with error %s", err)
+	case len(seq) != 2:
+		t.Errorf("should return actions of instance |%v|", actions)
+	case seq[0].Action != "system-update" || seq[1].Action != "db-upgrade":
+		t.Errorf("should not return unexpected actions |%v|", seq)
+	}
+}
@@ -10,9 +10,12 @@ package discovery
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sort"
 
-	"github.com/zalando/rds-health/internal/cache"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/zalando/rds-health/internal/cluster"
 	"github.com/zalando/rds-health/internal/database"
 	"github.com/zalando/rds-health/internal/instance"
@@ -26,7 +29,7 @@ import (
 type Discovery struct {
 	cluster  *cluster.Cluster
 	database *database.Database
-	instance *cache.Cache[string, *types.Compute]
+	instance *instance.Instance
 }
 
 func New(
@@ -37,47 +40,70 @@ func New(
 	return &Discovery{
 		cluster:  cluster.New(cp),
 		database: database.New(dp),
-		instance: cache.New(instance.New(ip)),
+		instance: instance.New(ip),
 	}
 }
 
-func (service *Discovery) LookupAll(ctx context.Context) ([]types.Cluster, []types.Node, error) {
-	allNodes, err := service.database.LookupAll(ctx)
+func (service *Discovery) LookupAll(ctx context.Context, filter types.Filter) ([]types.Cluster, []types.Node, error) {
+	allNodes, err := service.database.LookupAll(ctx, serverSideFilters(filter)...)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	mapNodes := make(map[string]types.Node)
+	classes := make([]string, 0)
 	for i := 0; i < len(allNodes); i++ {
 		node := allNodes[i]
-		node.Compute, _ = service.instance.Lookup(context.Background(), node.Type)
+		if !filter.Match(node) {
+			continue
+		}
 		mapNodes[node.Name] = node
+		classes = append(classes, node.Type)
+	}
+
+	// compute specs are optional, nodes are discovered with classes known
+	// to the catalog if EC2 fails
+	computes, err := service.instance.LookupAll(ctx, classes...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "instance classes are not resolved by EC2: %s\n", err)
+	}
+	for name, node := range mapNodes {
+		node.Compute = computes[node.Type]
+		mapNodes[name] = node
 	}
 
-	clusters, err := service.cluster.Lookup(context.Background())
+	allClusters, err := service.cluster.Lookup(ctx, serverSideFilters(filter)...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	for i := 0; i < len(clusters); i++ {
-		writers := make([]types.Node, len(clusters[i].Writer))
-		for k, w := range clusters[i].Writer {
+	clusters := make([]types.Cluster, 0, len(allClusters))
+	for _, cluster := range allClusters {
+		writers := make([]types.Node, 0, len(cluster.Writer))
+		for _, w := range cluster.Writer {
 			if node, has := mapNodes[w.Name]; has {
-				writers[k] = node
+				writers = append(writers, node)
 				delete(mapNodes, w.Name)
 			}
 		}
-		clusters[i].Writer = writers
+		cluster.Writer = writers
 
-		readers := make([]types.Node, len(clusters[i].Reader))
-		for k, r := range clusters[i].Reader {
+		readers := make([]types.Node, 0, len(cluster.Reader))
+		for _, r := range cluster.Reader {
 			if node, has := mapNodes[r.Name]; has {
 				node.ReadOnly = true
-				readers[k] = node
+				readers = append(readers, node)
 				delete(mapNodes, r.Name)
 			}
 		}
-		clusters[i].Reader = readers
+		cluster.Reader = readers
+
+		// cluster is not relevant if filter excludes all its members
+		if !filter.IsEmpty() && len(cluster.Writer)+len(cluster.Reader) == 0 {
+			continue
+		}
+
+		clusters = append(clusters, cluster)
 	}
 
 	nodes := make([]types.Node, 0, len(mapNodes))
@@ -89,3 +115,19 @@ func (service *Discovery) LookupAll(ctx context.Context) ([]types.Cluster, []typ
 	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
 	return clusters, nodes, nil
 }
+
+// translates filter to AWS API filters, both DescribeDBInstances and
+// DescribeDBClusters support filtering by engine and cluster identifier.
+func serverSideFilters(filter types.Filter) []rdstypes.Filter {
+	filters := make([]rdstypes.Filter, 0)
+
+	if len(filter.Engines) != 0 {
+		filters = append(filters, rdstypes.Filter{Name: aws.String("engine"), Values: filter.Engines})
+	}
+
+	if len(filter.Clusters) != 0 {
+		filters = append(filters, rdstypes.Filter{Name: aws.String("db-cluster-id"), Values: filter.Clusters})
+	}
+
+	return filters
+}
@@ -19,6 +19,7 @@ import (
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/zalando/rds-health/internal/discovery"
 	"github.com/zalando/rds-health/internal/mocks"
+	"github.com/zalando/rds-health/internal/types"
 	"go.uber.org/mock/gomock"
 )
 
@@ -58,7 +59,7 @@ func TestLookupAll(t *testing.T) {
 
 	sut := discovery.New(clusters, databases, instances)
 
-	c, n, err := sut.LookupAll(context.Background())
+	c, n, err := sut.LookupAll(context.Background(), types.Filter{})
 	switch {
 	case err != nil:
 		t.Errorf("should not failed with error %s", err)
@@ -94,6 +95,70 @@ func TestLookupAll(t *testing.T) {
 	}
 }
 
+func TestLookupAllWithFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	//
+	a := database("a")
+	a.TagList = []rdstypes.Tag{{Key: aws.String("team"), Value: aws.String("payments")}}
+	c := database("c")
+	c.TagList = []rdstypes.Tag{{Key: aws.String("team"), Value: aws.String("payments")}}
+	e := database("e")
+	e.TagList = []rdstypes.Tag{{Key: aws.String("team"), Value: aws.String("search")}}
+
+	dbs := &rds.DescribeDBInstancesOutput{
+		DBInstances: []rdstypes.DBInstance{a, database("b"), c, e},
+	}
+	databases := mocks.NewDatabase(ctrl)
+	databases.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, in *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+			if len(in.Filters) != 1 || aws.ToString(in.Filters[0].Name) != "engine" {
+				t.Errorf("should use server-side filter for engine |%v|", in.Filters)
+			}
+			return dbs, nil
+		},
+	)
+
+	//
+	cls := &rds.DescribeDBClustersOutput{
+		DBClusters: []rdstypes.DBCluster{
+			cluster("A", "a", ""),
+			cluster("B", "b", ""),
+		},
+	}
+	clusters := mocks.NewCluster(ctrl)
+	clusters.EXPECT().DescribeDBClusters(gomock.Any(), gomock.Any()).Return(cls, nil)
+
+	//
+	its := &ec2.DescribeInstanceTypesOutput{
+		InstanceTypes: []ec2types.InstanceTypeInfo{},
+	}
+	instances := mocks.NewInstance(ctrl)
+	instances.EXPECT().DescribeInstanceTypes(gomock.Any(), gomock.Any()).Return(its, nil)
+
+	sut := discovery.New(clusters, databases, instances)
+
+	filter := types.Filter{
+		Tags:    map[string]string{"team": "payments"},
+		Engines: []string{"postgres"},
+	}
+
+	cs, ns, err := sut.LookupAll(context.Background(), filter)
+	switch {
+	case err != nil:
+		t.Errorf("should not failed with error %s", err)
+	case len(cs) != 1:
+		t.Errorf("should return only clusters with matching members")
+	case cs[0].ID != "A":
+		t.Errorf("should not return unexpected cluster |%s|", cs[0].ID)
+	case len(ns) != 1:
+		t.Errorf("should return only matching databases")
+	case ns[0].Name != "c":
+		t.Errorf("should not return unexpected database |%s|", ns[0].Name)
+	}
+}
+
 //
 // Helper
 //
@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package encodes raw time series of Performance Insights metrics
+// into formats suitable for analysis with external tools. Each point is
+// a row (timestamp, metric, aggregator, value), missing values are empty.
+//
+
+// Encoder of time series
+type Encoder func(io.Writer, []types.Point) error
+
+// Encoders supported by the package
+var Encoders = map[string]Encoder{
+	"csv":     CSV,
+	"jsonl":   JSONL,
+	"parquet": Parquet,
+}
+
+// Lookup encoder by the format name or by extension of the file name
+func Lookup(format, file string) (Encoder, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+
+	if format == "" {
+		format = "csv"
+	}
+
+	enc, has := Encoders[format]
+	if !has {
+		return nil, fmt.Errorf("export format %s is not supported", format)
+	}
+
+	return enc, nil
+}
+
+// CSV with header
+func CSV(w io.Writer, seq []types.Point) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"timestamp", "metric", "aggregator", "value"}); err != nil {
+		return err
+	}
+
+	for _, x := range seq {
+		value := ""
+		if x.Value != nil {
+			value = strconv.FormatFloat(*x.Value, 'f', -1, 64)
+		}
+
+		err := out.Write([]string{x.Time.UTC().Format(time.RFC3339), x.Metric, x.Aggregator, value})
+		if err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// JSONL is JSON lines, one point per line
+func JSONL(w io.Writer, seq []types.Point) error {
+	out := json.NewEncoder(w)
+	for _, x := range seq {
+		x.Time = x.Time.UTC()
+		if err := out.Encode(x); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type row struct {
+	Time       time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	Metric     string    `parquet:"metric,dict"`
+	Aggregator string    `parquet:"aggregator,dict"`
+	Value      *float64  `parquet:"value,optional"`
+}
+
+// Parquet file, compressed with snappy
+func Parquet(w io.Writer, seq []types.Point) error {
+	rows := make([]row, len(seq))
+	for i, x := range seq {
+		rows[i] = row{Time: x.Time.UTC(), Metric: x.Metric, Aggregator: x.Aggregator, Value: x.Value}
+	}
+
+	out := parquet.NewGenericWriter[row](w, parquet.Compression(&parquet.Snappy))
+	if _, err := out.Write(rows); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
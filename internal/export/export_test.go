@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package export_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/zalando/rds-health/internal/export"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+var (
+	ts  = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	one = 1.5
+	seq = []types.Point{
+		{Time: ts, Metric: "db.load", Aggregator: "avg", Value: &one},
+		{Time: ts.Add(time.Minute), Metric: "db.load", Aggregator: "avg"},
+	}
+)
+
+func TestCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.CSV(buf, seq); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	expected := "timestamp,metric,aggregator,value\n" +
+		"2024-05-01T10:00:00Z,db.load,avg,1.5\n" +
+		"2024-05-01T10:01:00Z,db.load,avg,\n"
+
+	if buf.String() != expected {
+		t.Errorf("unexpected csv |%s|", buf.String())
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.JSONL(buf, seq); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	expected := `{"timestamp":"2024-05-01T10:00:00Z","metric":"db.load","aggregator":"avg","value":1.5}` + "\n" +
+		`{"timestamp":"2024-05-01T10:01:00Z","metric":"db.load","aggregator":"avg","value":null}` + "\n"
+
+	if buf.String() != expected {
+		t.Errorf("unexpected jsonl |%s|", buf.String())
+	}
+}
+
+func TestParquet(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.Parquet(buf, seq); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	if f.NumRows() != 2 {
+		t.Errorf("unexpected number of rows |%d|", f.NumRows())
+	}
+
+	for _, col := range []string{"timestamp", "metric", "aggregator", "value"} {
+		if _, has := f.Schema().Lookup(col); !has {
+			t.Errorf("should have column %s", col)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, tt := range []struct{ format, file string }{
+		{"", ""},
+		{"", "out.jsonl"},
+		{"", "out.parquet"},
+		{"csv", "out.txt"},
+	} {
+		if _, err := export.Lookup(tt.format, tt.file); err != nil {
+			t.Errorf("should lookup encoder for %s %s", tt.format, tt.file)
+		}
+	}
+
+	if _, err := export.Lookup("", "out.txt"); err == nil {
+		t.Errorf("should fail for unknown format")
+	}
+}
@@ -0,0 +1,268 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package keeps history of health status in the local store, which is
+// JSON lines file under the user's config directory. Every check appends
+// one record per node: the status and score of the node, the status,
+// success rate and soft average of each rule. Records are scoped by the
+// region and account of the node, cluster rules are records of own kind.
+//
+
+// DefaultFile of the history store
+func DefaultFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "rds-health", "history.jsonl"), nil
+}
+
+// Store of history records
+type Store struct {
+	file string
+}
+
+func New(file string) *Store {
+	return &Store{file: file}
+}
+
+// Append health status of the region observed at the time
+func (s *Store) Append(at time.Time, region types.StatusRegion) error {
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	write := func(kind, cluster string, n types.StatusNode) error {
+		return enc.Encode(recordOf(at, kind, cluster, n))
+	}
+
+	for _, c := range region.Clusters {
+		for _, n := range slices.Concat(c.Writer, c.Reader) {
+			if err := write(types.RECORD_NODE, c.Cluster.ID, n); err != nil {
+				return err
+			}
+		}
+
+		// cluster rules are recorded as the node named after the cluster
+		if len(c.Checks) != 0 {
+			if err := write(types.RECORD_CLUSTER, c.Cluster.ID, clusterOf(c)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, n := range region.Nodes {
+		if err := write(types.RECORD_NODE, n.Node.Cluster, n); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// status of cluster accounts cluster rules only, members are recorded on
+// their own
+func clusterOf(c types.StatusCluster) types.StatusNode {
+	node := types.StatusNode{Status: types.STATUS_CODE_UNKNOWN, Node: &types.Node{Name: c.Cluster.ID, ARN: c.Cluster.ARN}, Checks: c.Checks}
+	for _, s := range c.Checks {
+		node.Status = max(node.Status, s.Code)
+	}
+	return node
+}
+
+func recordOf(at time.Time, kind, cluster string, n types.StatusNode) types.Record {
+	r := types.Record{
+		Time:    at.UTC(),
+		Kind:    kind,
+		Node:    n.Node.Name,
+		Cluster: cluster,
+		Status:  n.Status,
+		Score:   valueOf(n.Score()),
+		Checks:  make([]types.RecordCheck, len(n.Checks)),
+	}
+
+	if id, err := arn.Parse(n.Node.ARN); err == nil {
+		r.Region, r.Account = id.Region, id.AccountID
+	}
+
+	for i, s := range n.Checks {
+		r.Checks[i] = types.RecordCheck{Rule: s.Rule, Status: s.Code, SuccessRate: s.SuccessRate}
+		if s.SoftMM != nil {
+			r.Checks[i].Avg = valueOf(s.SoftMM.Avg)
+		}
+	}
+
+	return r
+}
+
+func valueOf(x float64) *float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil
+	}
+	return &x
+}
+
+// Scope of records, records of other regions and accounts are skipped.
+// Empty fields match any, so do records kept without region and account.
+type Scope struct {
+	Region  string
+	Account string
+}
+
+func (scope Scope) match(r types.Record) bool {
+	return (scope.Region == "" || r.Region == "" || scope.Region == r.Region) &&
+		(scope.Account == "" || r.Account == "" || scope.Account == r.Account)
+}
+
+// Node reads records of the node ordered by time
+func (s *Store) Node(name string, scope Scope) ([]types.Record, error) {
+	return s.read(func(r types.Record) bool {
+		return r.Kind == types.RECORD_NODE && r.Node == name && scope.match(r)
+	})
+}
+
+// Cluster reads records of cluster rules ordered by time
+func (s *Store) Cluster(id string, scope Scope) ([]types.Record, error) {
+	return s.read(func(r types.Record) bool {
+		return r.Kind == types.RECORD_CLUSTER && r.Node == id && scope.match(r)
+	})
+}
+
+func (s *Store) read(match func(types.Record) bool) ([]types.Record, error) {
+	f, err := os.Open(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	seq := make([]types.Record, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		var r types.Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("invalid history %s:%d: %w", s.file, line, err)
+		}
+
+		if match(r) {
+			seq = append(seq, r)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(seq, func(a, b types.Record) int { return a.Time.Compare(b.Time) })
+	return seq, nil
+}
+
+// Compact drops records older than the time, the store is rewritten only
+// if there are records to drop.
+func (s *Store) Compact(before time.Time) error {
+	seq, err := s.read(func(types.Record) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	keep := slices.DeleteFunc(slices.Clone(seq), func(r types.Record) bool { return r.Time.Before(before) })
+	if len(keep) == len(seq) {
+		return nil
+	}
+
+	// concurrent checks never observe partially written store
+	f, err := os.CreateTemp(filepath.Dir(s.file), "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, r := range keep {
+		if err := enc.Encode(r); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.file)
+}
+
+// Of records builds the history of the node, failing are rules that do not
+// pass by the latest record with the streak of records.
+func Of(name string, records []types.Record) types.History {
+	h := types.History{Node: name, Records: records, Failing: make([]types.Streak, 0)}
+	if len(records) == 0 {
+		return h
+	}
+
+	key := func(c types.RecordCheck) string { return c.Rule.ID + "/" + c.Rule.About }
+
+	latest := records[len(records)-1]
+	for _, c := range latest.Checks {
+		if c.Status <= types.STATUS_CODE_SUCCESS {
+			continue
+		}
+
+		streak := types.Streak{Rule: c.Rule, Status: c.Status, Since: latest.Time, Until: latest.Time}
+		for i := len(records) - 1; i >= 0; i-- {
+			at := slices.IndexFunc(records[i].Checks, func(x types.RecordCheck) bool { return key(x) == key(c) })
+			if at == -1 || records[i].Checks[at].Status <= types.STATUS_CODE_SUCCESS {
+				break
+			}
+
+			streak.Since = records[i].Time
+			streak.Checks++
+		}
+
+		h.Failing = append(h.Failing, streak)
+	}
+
+	return h
+}
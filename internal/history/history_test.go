@@ -0,0 +1,186 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/history"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+const (
+	pass = types.STATUS_CODE_SUCCESS
+	warn = types.STATUS_CODE_WARNING
+	fail = types.STATUS_CODE_FAILURE
+)
+
+func region(c1, d3 types.StatusCode) types.StatusRegion {
+	return regionOf("eu-central-1", "111111111111", c1, d3)
+}
+
+func regionOf(region, account string, c1, d3 types.StatusCode) types.StatusRegion {
+	arn := func(kind, name string) string {
+		return "arn:aws:rds:" + region + ":" + account + ":" + kind + ":" + name
+	}
+
+	node := func(name string) types.StatusNode {
+		return types.StatusNode{
+			Status: max(c1, d3),
+			Node:   &types.Node{Name: name, ARN: arn("db", name)},
+			Checks: []types.Status{
+				{Code: c1, Rule: types.Rule{ID: "C1"}, SoftMM: &types.MinMax{Avg: 10.0}},
+				{Code: d3, Rule: types.Rule{ID: "D3"}},
+			},
+		}
+	}
+
+	return types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{
+				Cluster: &types.Cluster{ID: "c", ARN: arn("cluster", "c")},
+				Writer:  []types.StatusNode{node("a")},
+				Checks:  []types.Status{{Code: c1, Rule: types.Rule{ID: "R4"}}},
+			},
+		},
+		Nodes: []types.StatusNode{node("b")},
+	}
+}
+
+func TestHistory(t *testing.T) {
+	store := history.New(filepath.Join(t.TempDir(), "rds-health", "history.jsonl"))
+
+	if seq, err := store.Node("a", history.Scope{}); err != nil || len(seq) != 0 {
+		t.Fatalf("should read empty history |%v| %v", seq, err)
+	}
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i, codes := range [][2]types.StatusCode{{pass, fail}, {pass, pass}, {warn, fail}, {pass, fail}, {warn, warn}} {
+		if err := store.Append(at.Add(time.Duration(i)*time.Hour), region(codes[0], codes[1])); err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+	}
+
+	seq, err := store.Node("a", history.Scope{})
+	switch {
+	case err != nil:
+		t.Fatalf("should not fail with error %s", err)
+	case len(seq) != 5:
+		t.Fatalf("unexpected records |%+v|", seq)
+	case seq[0].Cluster != "c" || seq[0].Status != fail || *seq[0].Score != 0.5:
+		t.Errorf("unexpected record |%+v|", seq[0])
+	case seq[0].Region != "eu-central-1" || seq[0].Account != "111111111111":
+		t.Errorf("unexpected record |%+v|", seq[0])
+	case *seq[0].Checks[0].Avg != 10.0 || seq[0].Checks[1].Avg != nil:
+		t.Errorf("unexpected checks |%+v|", seq[0].Checks)
+	}
+
+	// cluster rules are recorded for the cluster
+	if seq, err := store.Cluster("c", history.Scope{}); err != nil || len(seq) != 5 || seq[2].Status != warn || seq[2].Kind != types.RECORD_CLUSTER || seq[2].Account != "111111111111" {
+		t.Errorf("unexpected records of cluster |%+v| %v", seq, err)
+	}
+	if seq, err := store.Node("c", history.Scope{}); err != nil || len(seq) != 0 {
+		t.Errorf("should not mix cluster rules with instances |%+v| %v", seq, err)
+	}
+
+	h := history.Of("a", seq)
+	if len(h.Failing) != 2 {
+		t.Fatalf("unexpected failing rules |%+v|", h.Failing)
+	}
+
+	c1, d3 := h.Failing[0], h.Failing[1]
+	switch {
+	case c1.Rule.ID != "C1" || c1.Checks != 1 || !c1.Since.Equal(at.Add(4*time.Hour)):
+		t.Errorf("unexpected streak |%+v|", c1)
+	case d3.Rule.ID != "D3" || d3.Status != warn || d3.Checks != 3 || !d3.Since.Equal(at.Add(2*time.Hour)):
+		t.Errorf("unexpected streak |%+v|", d3)
+	case !d3.Until.Equal(at.Add(4 * time.Hour)):
+		t.Errorf("unexpected end of streak |%+v|", d3)
+	}
+}
+
+func TestHistoryScope(t *testing.T) {
+	store := history.New(filepath.Join(t.TempDir(), "history.jsonl"))
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	store.Append(at, regionOf("eu-central-1", "111111111111", pass, pass))
+	store.Append(at, regionOf("eu-central-1", "222222222222", fail, fail))
+	store.Append(at, regionOf("eu-west-1", "111111111111", warn, warn))
+
+	for name, tt := range map[string]struct {
+		scope   history.Scope
+		records int
+	}{
+		"any":            {scope: history.Scope{}, records: 3},
+		"region":         {scope: history.Scope{Region: "eu-central-1"}, records: 2},
+		"account":        {scope: history.Scope{Account: "111111111111"}, records: 2},
+		"region account": {scope: history.Scope{Region: "eu-central-1", Account: "222222222222"}, records: 1},
+		"other region":   {scope: history.Scope{Region: "us-east-1"}, records: 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			seq, err := store.Node("a", tt.scope)
+			switch {
+			case err != nil:
+				t.Errorf("should not fail with error %s", err)
+			case len(seq) != tt.records:
+				t.Errorf("unexpected records |%+v|", seq)
+			}
+
+			for _, r := range seq {
+				if (tt.scope.Region != "" && r.Region != tt.scope.Region) || (tt.scope.Account != "" && r.Account != tt.scope.Account) {
+					t.Errorf("unexpected scope of record |%+v|", r)
+				}
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.jsonl")
+	store := history.New(file)
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		store.Append(at.Add(time.Duration(i)*24*time.Hour), region(pass, fail))
+	}
+
+	if err := store.Compact(at.Add(2 * 24 * time.Hour)); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	for _, lookup := range []func(string, history.Scope) ([]types.Record, error){store.Node, store.Cluster} {
+		for _, name := range []string{"a", "c"} {
+			seq, err := lookup(name, history.Scope{})
+			if err != nil {
+				t.Fatalf("should not fail with error %s", err)
+			}
+			for _, r := range seq {
+				if r.Time.Before(at.Add(2 * 24 * time.Hour)) {
+					t.Errorf("should drop old record |%+v|", r)
+				}
+			}
+		}
+	}
+
+	if seq, _ := store.Node("b", history.Scope{}); len(seq) != 2 {
+		t.Errorf("should keep recent records |%+v|", seq)
+	}
+
+	// nothing to drop, the store is not rewritten
+	info, _ := os.Stat(file)
+	if err := store.Compact(at); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+	if after, _ := os.Stat(file); !os.SameFile(info, after) {
+		t.Errorf("should not rewrite the store")
+	}
+}
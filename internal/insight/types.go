@@ -19,6 +19,7 @@ import (
 type Sample interface {
 	T() time.Time
 	X() float64
+	Has() bool
 }
 
 // Samples is a time series sequence
@@ -36,3 +37,4 @@ type sample types.DataPoint
 
 func (v sample) T() time.Time { return aws.ToTime(v.Timestamp) }
 func (v sample) X() float64   { return aws.ToFloat64(v.Value) }
+func (v sample) Has() bool    { return v.Value != nil }
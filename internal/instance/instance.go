@@ -10,11 +10,14 @@ package instance
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/zalando/rds-health/internal/catalog"
 	"github.com/zalando/rds-health/internal/types"
 )
 
@@ -29,33 +32,114 @@ type Provider interface {
 
 type Instance struct {
 	provider Provider
+	catalog  *catalog.Catalog
 }
 
 func New(provider Provider) *Instance {
-	return &Instance{provider: provider}
+	return &Instance{provider: provider, catalog: catalog.Default}
 }
 
-// Lookup metadata about the database
+// Lookup metadata about the database instance class. The embedded catalog
+// of RDS instance classes is used, EC2 is asked only for unknown classes.
 func (in *Instance) Lookup(ctx context.Context, dbInstanceType string) (*types.Compute, error) {
-	dbInstanceType = strings.TrimPrefix(dbInstanceType, "db.")
-
-	spec, err := in.provider.DescribeInstanceTypes(ctx,
-		&ec2.DescribeInstanceTypesInput{
-			InstanceTypes: []ec2types.InstanceType{
-				ec2types.InstanceType(dbInstanceType),
-			},
-		},
-	)
+	seq, err := in.LookupAll(ctx, dbInstanceType)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(spec.InstanceTypes) != 1 {
-		return nil, nil
+	return seq[dbInstanceType], nil
+}
+
+// Lookup metadata about multiple database instance classes, the classes
+// missing in the catalog are fetched from EC2 using a single batched call.
+// The classes resolved so far are returned along with the error of EC2.
+func (in *Instance) LookupAll(ctx context.Context, dbInstanceTypes ...string) (map[string]*types.Compute, error) {
+	computes := make(map[string]*types.Compute)
+	unknown := make([]ec2types.InstanceType, 0)
+
+	for _, class := range dbInstanceTypes {
+		if _, has := computes[class]; has {
+			continue
+		}
+
+		if spec, has := in.catalog.Lookup(class); has {
+			computes[class] = spec.Compute()
+			continue
+		}
+
+		computes[class] = nil
+
+		// RDS only classes (e.g. db.r5.large.tpc2.mem2x, db.serverless)
+		// have no EC2 equivalents, EC2 fails the entire call on them.
+		if strings.Count(class, ".") != 2 {
+			continue
+		}
+
+		unknown = append(unknown, ec2types.InstanceType(strings.TrimPrefix(class, "db.")))
+	}
+
+	// EC2 limits the number of instance types per request
+	for i := 0; i < len(unknown); i += 100 {
+		batch := unknown[i:min(i+100, len(unknown))]
+
+		seq, err := in.describe(ctx, batch)
+
+		// EC2 fails the entire call if any of types is not known to it (e.g.
+		// retired classes), the batch is looked up type by type skipping them
+		if isInvalidInstanceType(err) {
+			seq, err = nil, nil
+			for _, class := range batch {
+				spec, e := in.describe(ctx, []ec2types.InstanceType{class})
+				if isInvalidInstanceType(e) {
+					continue
+				}
+				if e != nil {
+					return computes, e
+				}
+				seq = append(seq, spec...)
+			}
+		}
+
+		if err != nil {
+			return computes, err
+		}
+
+		for _, instance := range seq {
+			computes["db."+string(instance.InstanceType)] = toCompute(instance)
+		}
+	}
+
+	return computes, nil
+}
+
+func (in *Instance) describe(ctx context.Context, classes []ec2types.InstanceType) ([]ec2types.InstanceTypeInfo, error) {
+	seq := make([]ec2types.InstanceTypeInfo, 0, len(classes))
+
+	var cursor *string
+	for do := true; do; do = cursor != nil {
+		spec, err := in.provider.DescribeInstanceTypes(ctx,
+			&ec2.DescribeInstanceTypesInput{
+				InstanceTypes: classes,
+				NextToken:     cursor,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		seq = append(seq, spec.InstanceTypes...)
+		cursor = spec.NextToken
 	}
 
-	instance := spec.InstanceTypes[0]
+	return seq, nil
+}
+
+func isInvalidInstanceType(err error) bool {
+	var api smithy.APIError
+	return errors.As(err, &api) && api.ErrorCode() == "InvalidInstanceType"
+}
 
+func toCompute(instance ec2types.InstanceTypeInfo) *types.Compute {
 	compute := types.Compute{}
 
 	if mem := instance.MemoryInfo; mem != nil {
@@ -75,5 +159,13 @@ func (in *Instance) Lookup(ctx context.Context, dbInstanceType string) (*types.C
 		compute.CPU.Clock = types.GHz(aws.ToFloat64(proc.SustainedClockSpeedInGhz))
 	}
 
-	return &compute, nil
+	if net := instance.NetworkInfo; net != nil && len(net.NetworkCards) != 0 {
+		compute.Network = types.Mbps(aws.ToFloat64(net.NetworkCards[0].PeakBandwidthInGbps) * 1000)
+	}
+
+	if ebs := instance.EbsInfo; ebs != nil && ebs.EbsOptimizedInfo != nil {
+		compute.EBS = types.Mbps(aws.ToInt32(ebs.EbsOptimizedInfo.MaximumBandwidthInMbps))
+	}
+
+	return &compute
 }
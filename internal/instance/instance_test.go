@@ -10,11 +10,13 @@ package instance_test
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
 	"github.com/zalando/rds-health/internal/instance"
 	"github.com/zalando/rds-health/internal/mocks"
 	"go.uber.org/mock/gomock"
@@ -27,6 +29,7 @@ func TestLookupSuccess(t *testing.T) {
 	fix := &ec2.DescribeInstanceTypesOutput{
 		InstanceTypes: []ec2types.InstanceTypeInfo{
 			{
+				InstanceType:  ec2types.InstanceTypeT2Small,
 				MemoryInfo:    &ec2types.MemoryInfo{SizeInMiB: aws.Int64(4 * 1024)},
 				VCpuInfo:      &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(2)},
 				ProcessorInfo: &ec2types.ProcessorInfo{SustainedClockSpeedInGhz: aws.Float64(2.20)},
@@ -71,3 +74,123 @@ func TestLookupNotFound(t *testing.T) {
 		t.Errorf("should return nil")
 	}
 }
+
+func TestLookupCatalog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mocks.NewInstance(ctrl)
+	mock.EXPECT().DescribeInstanceTypes(gomock.Any(), gomock.Any()).Times(0)
+
+	sut := instance.New(mock)
+
+	compute, err := sut.Lookup(context.TODO(), "db.m6g.large")
+	switch {
+	case err != nil:
+		t.Errorf("should not failed with error %s", err)
+	case compute == nil:
+		t.Errorf("should not return nil")
+	case compute.String() != "2 vcpu, mem 8 GiB, net 10 Gbps, ebs 4.75 Gbps":
+		t.Errorf("should not return unexpected value |%s|", compute)
+	}
+}
+
+func TestLookupAllBatched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fix := &ec2.DescribeInstanceTypesOutput{
+		InstanceTypes: []ec2types.InstanceTypeInfo{
+			{
+				InstanceType: ec2types.InstanceTypeT2Small,
+				MemoryInfo:   &ec2types.MemoryInfo{SizeInMiB: aws.Int64(2 * 1024)},
+				VCpuInfo:     &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(1)},
+			},
+			{
+				InstanceType: ec2types.InstanceTypeM4Large,
+				MemoryInfo:   &ec2types.MemoryInfo{SizeInMiB: aws.Int64(8 * 1024)},
+				VCpuInfo:     &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(2)},
+			},
+		},
+	}
+
+	mock := mocks.NewInstance(ctrl)
+	mock.EXPECT().DescribeInstanceTypes(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, in *ec2.DescribeInstanceTypesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+			if len(in.InstanceTypes) != 2 {
+				t.Errorf("should request unknown types only |%v|", in.InstanceTypes)
+			}
+			return fix, nil
+		},
+	).Times(1)
+
+	sut := instance.New(mock)
+
+	seq, err := sut.LookupAll(context.TODO(), "db.t2.small", "db.m4.large", "db.m6g.large", "db.t2.small", "db.r5.large.tpc2.mem2x")
+	switch {
+	case err != nil:
+		t.Errorf("should not failed with error %s", err)
+	case seq["db.t2.small"] == nil || seq["db.m4.large"] == nil || seq["db.m6g.large"] == nil:
+		t.Errorf("should return all known classes |%v|", seq)
+	case seq["db.r5.large.tpc2.mem2x"] != nil:
+		t.Errorf("should not return rds only class")
+	}
+}
+
+func TestLookupAllInvalidType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mocks.NewInstance(ctrl)
+	mock.EXPECT().DescribeInstanceTypes(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, in *ec2.DescribeInstanceTypesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+			if slices.Contains(in.InstanceTypes, "x1.large") {
+				return nil, &smithy.GenericAPIError{Code: "InvalidInstanceType", Message: "The following supplied instance types do not exist: [x1.large]"}
+			}
+
+			seq := make([]ec2types.InstanceTypeInfo, len(in.InstanceTypes))
+			for i, class := range in.InstanceTypes {
+				seq[i] = ec2types.InstanceTypeInfo{
+					InstanceType: class,
+					MemoryInfo:   &ec2types.MemoryInfo{SizeInMiB: aws.Int64(2 * 1024)},
+					VCpuInfo:     &ec2types.VCpuInfo{DefaultVCpus: aws.Int32(1)},
+				}
+			}
+			return &ec2.DescribeInstanceTypesOutput{InstanceTypes: seq}, nil
+		},
+	).Times(3)
+
+	sut := instance.New(mock)
+
+	seq, err := sut.LookupAll(context.TODO(), "db.t2.small", "db.x1.large")
+	switch {
+	case err != nil:
+		t.Errorf("should not failed with error %s", err)
+	case seq["db.t2.small"] == nil:
+		t.Errorf("should return known classes |%v|", seq)
+	case seq["db.x1.large"] != nil:
+		t.Errorf("should not return invalid class")
+	}
+}
+
+func TestLookupAllFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mocks.NewInstance(ctrl)
+	mock.EXPECT().DescribeInstanceTypes(gomock.Any(), gomock.Any()).Return(nil,
+		&smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not authorized to perform: ec2:DescribeInstanceTypes"},
+	)
+
+	sut := instance.New(mock)
+
+	seq, err := sut.LookupAll(context.TODO(), "db.m6g.large", "db.t2.small")
+	switch {
+	case err == nil:
+		t.Errorf("should fail with error")
+	case seq["db.m6g.large"] == nil:
+		t.Errorf("should keep classes of catalog |%v|", seq)
+	case seq["db.t2.small"] != nil:
+		t.Errorf("should not return unresolved class")
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package lifecycle
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package defines dataset about lifecycle of database engine versions.
+// The dataset is embedded into the application (see lifecycle.json), it is
+// updated by editing the file or replaced at runtime using Load.
+//
+
+//go:embed lifecycle.json
+var embedded []byte
+
+// Date of lifecycle event, encoded as YYYY-MM-DD
+type Date struct{ time.Time }
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+// Lifecycle of engine major (or minor) version
+type Version struct {
+	Engine       string `json:"engine"`
+	Version      string `json:"version"`
+	EndOfSupport Date   `json:"end_of_standard_support"`
+	Latest       string `json:"latest,omitempty"`
+}
+
+// Dataset of engine versions
+type Dataset struct {
+	Updated  string    `json:"updated"`
+	Versions []Version `json:"versions"`
+}
+
+// Default dataset used by the application
+var Default = must(Decode(embedded))
+
+func must(ds *Dataset, err error) *Dataset {
+	if err != nil {
+		panic(err)
+	}
+	return ds
+}
+
+// Decode dataset from JSON
+func Decode(data []byte) (*Dataset, error) {
+	var ds Dataset
+	if err := json.Unmarshal(data, &ds); err != nil {
+		return nil, err
+	}
+
+	return &ds, nil
+}
+
+// Load dataset from file
+func Load(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(data)
+}
+
+// Lookup lifecycle of the engine version, the most specific version wins
+// (e.g. 14.7 is preferred over 14 for engine version 14.7).
+func (ds *Dataset) Lookup(engine types.Engine) (*Version, bool) {
+	var found *Version
+	for i, v := range ds.Versions {
+		if v.Engine != engine.ID {
+			continue
+		}
+
+		if engine.Version != v.Version && !strings.HasPrefix(engine.Version, v.Version+".") {
+			continue
+		}
+
+		if found == nil || len(v.Version) > len(found.Version) {
+			found = &ds.Versions[i]
+		}
+	}
+
+	return found, found != nil
+}
+
+// Compare dotted versions, numeric segments are compared as numbers.
+// It returns -1 if a < b, 0 if a == b, +1 if a > b.
+func Compare(a, b string) int {
+	sa := strings.Split(a, ".")
+	sb := strings.Split(b, ".")
+
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		na, ea := strconv.Atoi(sa[i])
+		nb, eb := strconv.Atoi(sb[i])
+
+		switch {
+		case ea == nil && eb == nil && na < nb:
+			return -1
+		case ea == nil && eb == nil && na > nb:
+			return 1
+		case (ea != nil || eb != nil) && sa[i] < sb[i]:
+			return -1
+		case (ea != nil || eb != nil) && sa[i] > sb[i]:
+			return 1
+		}
+	}
+
+	switch {
+	case len(sa) < len(sb):
+		return -1
+	case len(sa) > len(sb):
+		return 1
+	default:
+		return 0
+	}
+}
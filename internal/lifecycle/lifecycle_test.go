@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package lifecycle_test
+
+import (
+	"testing"
+
+	"github.com/zalando/rds-health/internal/lifecycle"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestLookup(t *testing.T) {
+	ds, err := lifecycle.Decode([]byte(`{
+		"versions": [
+			{"engine": "postgres", "version": "14", "end_of_standard_support": "2027-02-28", "latest": "14.15"},
+			{"engine": "postgres", "version": "14.1", "end_of_standard_support": "2024-03-31"},
+			{"engine": "aurora-mysql", "version": "8.0.mysql_aurora.3", "end_of_standard_support": "2028-04-30"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	for engine, expected := range map[types.Engine]string{
+		{ID: "postgres", Version: "14.7"}:                        "14",
+		{ID: "postgres", Version: "14.1"}:                        "14.1",
+		{ID: "postgres", Version: "14.10"}:                       "14",
+		{ID: "aurora-mysql", Version: "8.0.mysql_aurora.3.05.2"}: "8.0.mysql_aurora.3",
+		{ID: "postgres", Version: "15.2"}:                        "",
+		{ID: "mysql", Version: "14.7"}:                           "",
+	} {
+		v, has := ds.Lookup(engine)
+		switch {
+		case expected == "" && has:
+			t.Errorf("should not find %s %s |%s|", engine.ID, engine.Version, v.Version)
+		case expected != "" && (!has || v.Version != expected):
+			t.Errorf("should find %s for %s %s", expected, engine.ID, engine.Version)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, tt := range []struct {
+		a, b   string
+		expect int
+	}{
+		{"14.7", "14.15", -1},
+		{"14.15", "14.7", 1},
+		{"14.7", "14.7", 0},
+		{"14", "14.7", -1},
+		{"8.0.mysql_aurora.3.05.2", "8.0.mysql_aurora.3.08.0", -1},
+	} {
+		if v := lifecycle.Compare(tt.a, tt.b); v != tt.expect {
+			t.Errorf("unexpected compare of %s and %s |%d|", tt.a, tt.b, v)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if len(lifecycle.Default.Versions) == 0 {
+		t.Errorf("should embed dataset")
+	}
+}
@@ -1,5 +1,10 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: github.com/zalando/rds-health/internal/database (interfaces: Provider)
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/database.go -package=mocks -mock_names Provider=Database . Provider
+//
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -38,7 +43,7 @@ func (m *Database) EXPECT() *DatabaseMockRecorder {
 // DescribeDBInstances mocks base method.
 func (m *Database) DescribeDBInstances(arg0 context.Context, arg1 *rds.DescribeDBInstancesInput, arg2 ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{arg0, arg1}
+	varargs := []any{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
@@ -49,8 +54,28 @@ func (m *Database) DescribeDBInstances(arg0 context.Context, arg1 *rds.DescribeD
 }
 
 // DescribeDBInstances indicates an expected call of DescribeDBInstances.
-func (mr *DatabaseMockRecorder) DescribeDBInstances(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+func (mr *DatabaseMockRecorder) DescribeDBInstances(arg0, arg1 any, arg2 ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	varargs := append([]any{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBInstances", reflect.TypeOf((*Database)(nil).DescribeDBInstances), varargs...)
 }
+
+// DescribePendingMaintenanceActions mocks base method.
+func (m *Database) DescribePendingMaintenanceActions(arg0 context.Context, arg1 *rds.DescribePendingMaintenanceActionsInput, arg2 ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribePendingMaintenanceActions", varargs...)
+	ret0, _ := ret[0].(*rds.DescribePendingMaintenanceActionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribePendingMaintenanceActions indicates an expected call of DescribePendingMaintenanceActions.
+func (mr *DatabaseMockRecorder) DescribePendingMaintenanceActions(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribePendingMaintenanceActions", reflect.TypeOf((*Database)(nil).DescribePendingMaintenanceActions), varargs...)
+}
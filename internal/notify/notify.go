@@ -0,0 +1,390 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package sends notifications about health status of instances to
+// webhooks. Alerts of single check are aggregated into one message, the
+// text of message is rendered using Go template. Repeated alerts are
+// suppressed within the deduplication window, the state of notifications
+// is persisted so that it survives across runs of the utility.
+//
+
+// Policy of notifications
+type Policy string
+
+const (
+	ON_WARN   = Policy("warn")   // instances that warn or fail
+	ON_FAIL   = Policy("fail")   // instances that fail
+	ON_CHANGE = Policy("change") // instances that change status, including recoveries
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case ON_WARN, ON_FAIL, ON_CHANGE:
+		return p, nil
+	default:
+		return "", fmt.Errorf("notification policy %s is not supported, use warn, fail or change", s)
+	}
+}
+
+// Alert about the instance
+type Alert struct {
+	Node    string           `json:"node"`
+	Cluster string           `json:"cluster,omitempty"`
+	From    types.StatusCode `json:"from"`
+	Status  types.StatusCode `json:"status"`
+	Rules   []types.Status   `json:"rules,omitempty"` // rules that do not pass
+
+	key string // key of the state, the cluster and its instance are named alike
+}
+
+// alerts are duplicates if instance has same status caused by same rules
+func (a Alert) fingerprint() string {
+	seq := []string{a.Node, a.Status.String()}
+	for _, s := range a.Rules {
+		seq = append(seq, s.Rule.ID+"="+s.Code.String())
+	}
+	return strings.Join(seq, " ")
+}
+
+// Message about alerts of the single check
+type Message struct {
+	Time   time.Time        `json:"time"`
+	Status types.StatusCode `json:"status"` // the worst status of alerts
+	Text   string           `json:"text"`
+	Alerts []Alert          `json:"alerts"`
+}
+
+// Sink of notifications
+type Sink interface {
+	Send(context.Context, Message) error
+}
+
+//
+// Template
+//
+
+// DefaultTemplate of the message text
+const DefaultTemplate = `rds-health: {{len .Alerts}} instance(s) {{status .Status}}
+{{range .Alerts}}{{icon .Status}}{{status .From}} → {{status .Status}} {{.Node}}{{range .Rules}} {{.Rule.ID}}{{end}}
+{{end}}`
+
+// Template of the message text. Message is the data of template, the
+// helper functions are
+//
+//	status  text of status code (PASS, WARN, FAIL, NONE)
+//	icon    emoji of status code
+//	join    strings.Join
+func Template(text string) (*template.Template, error) {
+	return template.New("message").Funcs(template.FuncMap{
+		"status": statusText,
+		"icon":   statusIcon,
+		"join":   strings.Join,
+	}).Parse(text)
+}
+
+func statusText(code types.StatusCode) string {
+	switch code {
+	case types.STATUS_CODE_SUCCESS:
+		return "PASS"
+	case types.STATUS_CODE_WARNING:
+		return "WARN"
+	case types.STATUS_CODE_FAILURE:
+		return "FAIL"
+	default:
+		return "NONE"
+	}
+}
+
+func statusIcon(code types.StatusCode) string {
+	switch code {
+	case types.STATUS_CODE_SUCCESS:
+		return show.SCHEMA_COLOR.StatusCodeIcon.PASS
+	case types.STATUS_CODE_WARNING:
+		return show.SCHEMA_COLOR.StatusCodeIcon.WARN
+	case types.STATUS_CODE_FAILURE:
+		return show.SCHEMA_COLOR.StatusCodeIcon.FAIL
+	default:
+		return show.SCHEMA_COLOR.StatusCodeIcon.NONE
+	}
+}
+
+//
+// Notifier
+//
+
+// Option of the notifier
+type Option func(*Notifier)
+
+// WithPolicy defines which instances are notified about (default fail)
+func WithPolicy(policy Policy) Option {
+	return func(n *Notifier) { n.policy = policy }
+}
+
+// WithTemplate defines the text of messages
+func WithTemplate(t *template.Template) Option {
+	return func(n *Notifier) { n.template = t }
+}
+
+// WithDedup suppresses repeated alerts within the window (default 1h)
+func WithDedup(window time.Duration) Option {
+	return func(n *Notifier) { n.dedup = window }
+}
+
+// WithState persists the state of notifications to the file
+func WithState(file string) Option {
+	return func(n *Notifier) { n.file = file }
+}
+
+// Notifier of health status
+type Notifier struct {
+	sinks    []Sink
+	policy   Policy
+	template *template.Template
+	dedup    time.Duration
+	file     string
+	state    map[string]state
+}
+
+// state of notifications about the instance
+type state struct {
+	Status      types.StatusCode `json:"status"`
+	Fingerprint string           `json:"fingerprint,omitempty"`
+	Sent        time.Time        `json:"sent,omitempty"`
+}
+
+func New(sinks []Sink, opts ...Option) (*Notifier, error) {
+	n := &Notifier{
+		sinks:  sinks,
+		policy: ON_FAIL,
+		dedup:  time.Hour,
+		state:  make(map[string]state),
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if n.template == nil {
+		n.template = template.Must(Template(DefaultTemplate))
+	}
+
+	if n.file != "" {
+		b, err := os.ReadFile(n.file)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return nil, err
+		default:
+			// corrupted state is same as no state
+			json.Unmarshal(b, &n.state)
+		}
+	}
+
+	return n, nil
+}
+
+// Notify about health status of the region, according to the policy
+func (n *Notifier) Notify(ctx context.Context, at time.Time, region types.StatusRegion) error {
+	alerts := make([]Alert, 0)
+	next := make(map[string]state)
+
+	observe := func(key, cluster string, node types.StatusNode) {
+		prev, has := n.state[key]
+
+		alert := Alert{Node: node.Node.Name, Cluster: cluster, From: prev.Status, Status: node.Status, key: key}
+		for _, s := range node.Checks {
+			if s.Code > types.STATUS_CODE_SUCCESS {
+				alert.Rules = append(alert.Rules, s)
+			}
+		}
+
+		fp := alert.fingerprint()
+		send := false
+		switch n.policy {
+		case ON_WARN:
+			send = node.Status >= types.STATUS_CODE_WARNING
+		case ON_FAIL:
+			send = node.Status == types.STATUS_CODE_FAILURE
+		case ON_CHANGE:
+			send = (has && prev.Status != node.Status) || (!has && node.Status > types.STATUS_CODE_SUCCESS)
+		}
+
+		if send && prev.Fingerprint == fp && at.Sub(prev.Sent) < n.dedup {
+			send = false
+		}
+
+		// recovered instances are notified again about any repeated failure
+		next[key] = state{Status: node.Status}
+		if node.Status > types.STATUS_CODE_SUCCESS {
+			next[key] = state{Status: node.Status, Fingerprint: prev.Fingerprint, Sent: prev.Sent}
+		}
+
+		if send {
+			alerts = append(alerts, alert)
+			next[key] = state{Status: node.Status, Fingerprint: fp, Sent: at}
+		}
+	}
+
+	for _, c := range region.Clusters {
+		for _, node := range slices.Concat(c.Writer, c.Reader) {
+			observe(node.Node.Name, c.Cluster.ID, node)
+		}
+		if len(c.Checks) != 0 {
+			observe("cluster:"+c.Cluster.ID, c.Cluster.ID, clusterOf(c))
+		}
+	}
+
+	for _, node := range region.Nodes {
+		observe(node.Node.Name, "", node)
+	}
+
+	if len(alerts) != 0 {
+		if err := n.send(ctx, at, alerts); err != nil {
+			// alerts are not marked as sent, they are retried next time
+			for _, alert := range alerts {
+				prev := n.state[alert.key]
+				next[alert.key] = state{Status: alert.Status, Fingerprint: prev.Fingerprint, Sent: prev.Sent}
+			}
+			n.update(next)
+			return err
+		}
+	}
+
+	return n.update(next)
+}
+
+// cluster rules are observed as the node named after the cluster, its status
+// accounts cluster rules only, members are observed on their own
+func clusterOf(c types.StatusCluster) types.StatusNode {
+	node := types.StatusNode{Status: types.STATUS_CODE_UNKNOWN, Node: &types.Node{Name: c.Cluster.ID}, Checks: c.Checks}
+	for _, s := range c.Checks {
+		node.Status = max(node.Status, s.Code)
+	}
+	return node
+}
+
+func (n *Notifier) send(ctx context.Context, at time.Time, alerts []Alert) error {
+	msg := Message{Time: at, Status: types.STATUS_CODE_UNKNOWN, Alerts: alerts}
+	for _, alert := range alerts {
+		msg.Status = max(msg.Status, alert.Status)
+	}
+
+	text := &bytes.Buffer{}
+	if err := n.template.Execute(text, msg); err != nil {
+		return err
+	}
+	msg.Text = text.String()
+
+	var errs []error
+	for _, sink := range n.sinks {
+		if err := sink.Send(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// instances that are not checked this time keep their state
+func (n *Notifier) update(next map[string]state) error {
+	for name, s := range next {
+		n.state[name] = s
+	}
+
+	if n.file == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(n.state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(n.file), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(n.file, b, 0o600)
+}
+
+//
+// Sinks
+//
+
+// Webhook receives the message as JSON
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w Webhook) Send(ctx context.Context, msg Message) error {
+	return post(ctx, w.Client, w.URL, msg)
+}
+
+// Slack incoming webhook receives the text of message
+type Slack struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s Slack) Send(ctx context.Context, msg Message) error {
+	return post(ctx, s.Client, s.URL, struct {
+		Text string `json:"text"`
+	}{msg.Text})
+}
+
+func post(ctx context.Context, client *http.Client, url string, val any) error {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notification to %s failed: %s", req.URL.Host, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/notify"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// local stub of webhook, it records received payloads
+type stub struct {
+	*httptest.Server
+	mu   sync.Mutex
+	code int
+	seq  []map[string]any
+}
+
+func newStub(t *testing.T) *stub {
+	s := &stub{code: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		var val map[string]any
+		if err := json.Unmarshal(b, &val); err != nil {
+			t.Errorf("should receive json %s", err)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.code == http.StatusOK {
+			s.seq = append(s.seq, val)
+		}
+		w.WriteHeader(s.code)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func region(codes map[string]types.StatusCode) types.StatusRegion {
+	r := types.StatusRegion{}
+	for _, name := range []string{"a", "b"} {
+		code, has := codes[name]
+		if !has {
+			continue
+		}
+
+		r.Nodes = append(r.Nodes, types.StatusNode{
+			Status: code,
+			Node:   &types.Node{Name: name},
+			Checks: []types.Status{{Code: code, Rule: types.Rule{ID: "D3", About: "storage i/o latency"}}},
+		})
+	}
+	return r
+}
+
+func TestWebhook(t *testing.T) {
+	hook := newStub(t)
+	n, err := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}}, notify.WithPolicy(notify.ON_WARN))
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	err = n.Notify(context.Background(), time.Now(), region(map[string]types.StatusCode{
+		"a": types.STATUS_CODE_SUCCESS,
+		"b": types.STATUS_CODE_WARNING,
+	}))
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	if len(hook.seq) != 1 {
+		t.Fatalf("should send one message |%v|", hook.seq)
+	}
+
+	msg := hook.seq[0]
+	alerts := msg["alerts"].([]any)
+	switch {
+	case msg["status"] != "warned":
+		t.Errorf("unexpected status |%v|", msg["status"])
+	case len(alerts) != 1 || alerts[0].(map[string]any)["node"] != "b":
+		t.Errorf("unexpected alerts |%v|", alerts)
+	case !strings.Contains(msg["text"].(string), "NONE → WARN b D3"):
+		t.Errorf("unexpected text |%v|", msg["text"])
+	}
+}
+
+func TestSlackTemplate(t *testing.T) {
+	slack := newStub(t)
+	tmpl, err := notify.Template(`{{range .Alerts}}{{.Node}} is {{status .Status}}{{end}}`)
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	n, _ := notify.New([]notify.Sink{notify.Slack{URL: slack.URL}}, notify.WithTemplate(tmpl))
+	n.Notify(context.Background(), time.Now(), region(map[string]types.StatusCode{"a": types.STATUS_CODE_FAILURE}))
+
+	if len(slack.seq) != 1 || slack.seq[0]["text"] != "a is FAIL" || len(slack.seq[0]) != 1 {
+		t.Errorf("unexpected slack payload |%v|", slack.seq)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	hook := newStub(t)
+	file := filepath.Join(t.TempDir(), "notify.json")
+	at := time.Now()
+
+	fail := region(map[string]types.StatusCode{"a": types.STATUS_CODE_FAILURE})
+	pass := region(map[string]types.StatusCode{"a": types.STATUS_CODE_SUCCESS})
+
+	for i, tt := range []struct {
+		region types.StatusRegion
+		after  time.Duration
+		sent   int
+	}{
+		{fail, 0, 1},
+		{fail, 10 * time.Minute, 1}, // duplicate within window
+		{fail, 2 * time.Hour, 2},    // window is expired
+		{pass, 2 * time.Hour, 2},    // recovery is not notified
+		{fail, 2*time.Hour + 1, 3},  // repeated failure after recovery
+	} {
+		// new notifier emulates independent runs of the utility
+		n, _ := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}}, notify.WithState(file))
+		if err := n.Notify(context.Background(), at.Add(tt.after), tt.region); err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+
+		if len(hook.seq) != tt.sent {
+			t.Errorf("%d: unexpected number of messages |%d|", i, len(hook.seq))
+		}
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	hook := newStub(t)
+	n, _ := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}}, notify.WithPolicy(notify.ON_CHANGE))
+	at := time.Now()
+
+	n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": types.STATUS_CODE_SUCCESS}))
+	n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": types.STATUS_CODE_SUCCESS}))
+	if len(hook.seq) != 0 {
+		t.Errorf("should not notify without changes |%v|", hook.seq)
+	}
+
+	n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": types.STATUS_CODE_WARNING}))
+	n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": types.STATUS_CODE_SUCCESS}))
+	if len(hook.seq) != 2 {
+		t.Fatalf("should notify degradation and recovery |%v|", hook.seq)
+	}
+
+	alert := hook.seq[1]["alerts"].([]any)[0].(map[string]any)
+	if alert["from"] != "warned" || alert["status"] != "passed" {
+		t.Errorf("unexpected recovery |%v|", alert)
+	}
+}
+
+func TestOnChangeAcrossRuns(t *testing.T) {
+	hook := newStub(t)
+	file := filepath.Join(t.TempDir(), "notify.json")
+	at := time.Now()
+
+	for _, code := range []types.StatusCode{types.STATUS_CODE_WARNING, types.STATUS_CODE_WARNING, types.STATUS_CODE_SUCCESS} {
+		n, _ := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}}, notify.WithPolicy(notify.ON_CHANGE), notify.WithState(file))
+		n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": code}))
+	}
+
+	if len(hook.seq) != 2 {
+		t.Errorf("should notify degradation and recovery only |%v|", hook.seq)
+	}
+}
+
+func TestRetryOnFailure(t *testing.T) {
+	hook := newStub(t)
+	n, _ := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}})
+	at := time.Now()
+
+	hook.code = http.StatusInternalServerError
+	if err := n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": types.STATUS_CODE_FAILURE})); err == nil {
+		t.Errorf("should fail with error")
+	}
+
+	hook.code = http.StatusOK
+	n.Notify(context.Background(), at, region(map[string]types.StatusCode{"a": types.STATUS_CODE_FAILURE}))
+	if len(hook.seq) != 1 {
+		t.Errorf("should retry failed notification |%v|", hook.seq)
+	}
+}
+
+func TestClusterRules(t *testing.T) {
+	hook := newStub(t)
+	n, _ := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}})
+
+	observed := "eu-central-1a"
+	r := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{
+				Status:  types.STATUS_CODE_FAILURE,
+				Cluster: &types.Cluster{ID: "c"},
+				Writer:  region(map[string]types.StatusCode{"a": types.STATUS_CODE_SUCCESS}).Nodes,
+				Checks: []types.Status{
+					{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "R4", About: "members in single zone"}, Observed: &observed},
+				},
+			},
+		},
+	}
+
+	if err := n.Notify(context.Background(), time.Now(), r); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	if len(hook.seq) != 1 {
+		t.Fatalf("should notify about cluster |%v|", hook.seq)
+	}
+
+	alerts := hook.seq[0]["alerts"].([]any)
+	alert := alerts[0].(map[string]any)
+	if len(alerts) != 1 || alert["node"] != "c" || alert["cluster"] != "c" || alert["status"] != "failed" {
+		t.Errorf("unexpected alert of cluster |%v|", alerts)
+	}
+}
+
+func TestClusterRulesDedup(t *testing.T) {
+	hook := newStub(t)
+	file := filepath.Join(t.TempDir(), "notify.json")
+	at := time.Now()
+
+	// cluster and its writer are named alike
+	r := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{
+				Cluster: &types.Cluster{ID: "a"},
+				Writer:  region(map[string]types.StatusCode{"a": types.STATUS_CODE_FAILURE}).Nodes,
+				Checks:  []types.Status{{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "R4"}}},
+			},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		n, _ := notify.New([]notify.Sink{notify.Webhook{URL: hook.URL}}, notify.WithState(file))
+		if err := n.Notify(context.Background(), at.Add(time.Duration(i)*time.Minute), r); err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+	}
+
+	switch {
+	case len(hook.seq) != 1:
+		t.Errorf("should suppress repeated alerts |%v|", hook.seq)
+	case len(hook.seq[0]["alerts"].([]any)) != 2:
+		t.Errorf("should alert about instance and cluster |%v|", hook.seq[0])
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if _, err := notify.ParsePolicy("sometimes"); err == nil {
+		t.Errorf("should fail for unknown policy")
+	}
+}
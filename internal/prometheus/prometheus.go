@@ -0,0 +1,296 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package prometheus
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package exposes health status of the region as Prometheus metrics
+// using the text exposition format. The region is checked periodically in
+// the background, scrapes are served from the latest snapshot so that they
+// never trigger requests to AWS APIs.
+//
+
+// Checker of the region health
+type Checker interface {
+	CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error)
+}
+
+// Snapshot of the region health at the moment of refresh
+type Snapshot struct {
+	Region   *types.StatusRegion
+	Time     time.Time     // completion of the latest refresh
+	Duration time.Duration // duration of the latest refresh
+	Error    error         // error of the latest refresh
+	Refresh  int           // total number of refreshes
+	Failures int           // total number of failed refreshes
+}
+
+// Exporter of the region health
+type Exporter struct {
+	checker  Checker
+	filter   types.Filter
+	interval time.Duration
+
+	mu       sync.Mutex
+	snapshot Snapshot
+}
+
+func New(checker Checker, filter types.Filter, interval time.Duration) *Exporter {
+	return &Exporter{
+		checker:  checker,
+		filter:   filter,
+		interval: interval,
+	}
+}
+
+// Snapshot of the latest refresh
+func (e *Exporter) Snapshot() Snapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.snapshot
+}
+
+// Refresh health status of the region. The previous status is kept if
+// refresh fails.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	t := time.Now()
+	region, err := e.checker.CheckHealthRegion(ctx, e.filter, e.interval)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.snapshot.Time = time.Now()
+	e.snapshot.Duration = e.snapshot.Time.Sub(t)
+	e.snapshot.Error = err
+	e.snapshot.Refresh++
+
+	if err != nil {
+		e.snapshot.Failures++
+		return err
+	}
+
+	e.snapshot.Region = region
+	return nil
+}
+
+// Run refresh periodically until the context is cancelled
+func (e *Exporter) Run(ctx context.Context, every time.Duration, onError func(error)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if err := Write(w, e.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+//
+// Text exposition format
+//
+
+// Write the snapshot using Prometheus text exposition format
+func Write(w io.Writer, snapshot Snapshot) error {
+	out := &writer{w: bufio.NewWriter(w)}
+
+	up := 0.0
+	if snapshot.Region != nil && snapshot.Error == nil {
+		up = 1.0
+	}
+
+	out.family("rds_health_up", "gauge", "1 if the latest refresh of health status succeeded")
+	out.sample("rds_health_up", nil, up)
+
+	out.family("rds_health_refresh_timestamp_seconds", "gauge", "completion time of the latest refresh")
+	if !snapshot.Time.IsZero() {
+		out.sample("rds_health_refresh_timestamp_seconds", nil, float64(snapshot.Time.UnixMilli())/1000)
+	}
+
+	out.family("rds_health_refresh_duration_seconds", "gauge", "duration of the latest refresh")
+	out.sample("rds_health_refresh_duration_seconds", nil, snapshot.Duration.Seconds())
+
+	out.family("rds_health_refresh_total", "counter", "total number of refreshes")
+	out.sample("rds_health_refresh_total", nil, float64(snapshot.Refresh))
+
+	out.family("rds_health_refresh_failures_total", "counter", "total number of failed refreshes")
+	out.sample("rds_health_refresh_failures_total", nil, float64(snapshot.Failures))
+
+	if snapshot.Region == nil {
+		return out.flush()
+	}
+
+	nodes := nodesOf(*snapshot.Region)
+
+	out.family("rds_health_region_status", "gauge", "health status of the region (0 unknown, 1 passed, 2 warned, 3 failed)")
+	out.sample("rds_health_region_status", nil, float64(snapshot.Region.Status))
+
+	out.family("rds_health_cluster_status", "gauge", "health status of the cluster (0 unknown, 1 passed, 2 warned, 3 failed)")
+	for _, c := range snapshot.Region.Clusters {
+		out.sample("rds_health_cluster_status", labels{"cluster", c.Cluster.ID}, float64(c.Status))
+	}
+
+	out.family("rds_health_node_info", "gauge", "database instance")
+	for _, n := range nodes {
+		engine, version := "", ""
+		if n.Node.Engine != nil {
+			engine, version = n.Node.Engine.ID, n.Node.Engine.Version
+		}
+		out.sample("rds_health_node_info", append(append(labels{}, n.labels...), "class", n.Node.Type, "engine", engine, "version", version), 1)
+	}
+
+	out.family("rds_health_node_status", "gauge", "health status of the instance (0 unknown, 1 passed, 2 warned, 3 failed)")
+	for _, n := range nodes {
+		out.sample("rds_health_node_status", n.labels, float64(n.Status))
+	}
+
+	out.family("rds_health_node_score", "gauge", "share of rules passed by the instance (0..1)")
+	for _, n := range nodes {
+		out.sample("rds_health_node_score", n.labels, n.Score())
+	}
+
+	out.family("rds_health_rule_status", "gauge", "health status of the rule (0 unknown, 1 passed, 2 warned, 3 failed)")
+	for _, n := range nodes {
+		for _, s := range n.Checks {
+			out.sample("rds_health_rule_status", n.rule(s), float64(s.Code))
+		}
+	}
+
+	// cluster rules are not bound to any node
+	for _, c := range snapshot.Region.Clusters {
+		n := node{labels: labels{"node", "", "cluster", c.Cluster.ID, "role", "cluster"}}
+		for _, s := range c.Checks {
+			out.sample("rds_health_rule_status", n.rule(s), float64(s.Code))
+		}
+	}
+
+	out.family("rds_health_rule_success_rate", "gauge", "percent of time the rule is passed")
+	for _, n := range nodes {
+		for _, s := range n.Checks {
+			if s.SuccessRate != nil {
+				out.sample("rds_health_rule_success_rate", n.rule(s), *s.SuccessRate)
+			}
+		}
+	}
+
+	out.family("rds_health_rule_value", "gauge", "soft min, avg and max of the metric observed by the rule")
+	for _, n := range nodes {
+		for _, s := range n.Checks {
+			if s.SoftMM != nil {
+				out.sample("rds_health_rule_value", append(n.rule(s), "stat", "min"), s.SoftMM.Min)
+				out.sample("rds_health_rule_value", append(n.rule(s), "stat", "avg"), s.SoftMM.Avg)
+				out.sample("rds_health_rule_value", append(n.rule(s), "stat", "max"), s.SoftMM.Max)
+			}
+		}
+	}
+
+	return out.flush()
+}
+
+// node with its labels
+type node struct {
+	types.StatusNode
+	labels labels
+}
+
+func (n node) rule(s types.Status) labels {
+	return append(append(labels{}, n.labels...), "rule", s.Rule.ID, "about", s.Rule.About, "unit", s.Rule.Unit)
+}
+
+func nodesOf(region types.StatusRegion) []node {
+	seq := make([]node, 0)
+
+	for _, c := range region.Clusters {
+		for _, n := range c.Writer {
+			seq = append(seq, node{n, labels{"node", n.Node.Name, "cluster", c.Cluster.ID, "role", "writer"}})
+		}
+		for _, n := range c.Reader {
+			seq = append(seq, node{n, labels{"node", n.Node.Name, "cluster", c.Cluster.ID, "role", "reader"}})
+		}
+	}
+
+	for _, n := range region.Nodes {
+		seq = append(seq, node{n, labels{"node", n.Node.Name, "cluster", "", "role", "instance"}})
+	}
+
+	return seq
+}
+
+// sequence of label name, value pairs
+type labels []string
+
+type writer struct {
+	w *bufio.Writer
+}
+
+func (out *writer) family(name, kind, help string) {
+	out.w.WriteString("# HELP " + name + " " + help + "\n")
+	out.w.WriteString("# TYPE " + name + " " + kind + "\n")
+}
+
+func (out *writer) sample(name string, labels labels, value float64) {
+	out.w.WriteString(name)
+
+	if len(labels) != 0 {
+		out.w.WriteString("{")
+		for i := 0; i < len(labels); i += 2 {
+			if i != 0 {
+				out.w.WriteString(",")
+			}
+			out.w.WriteString(labels[i] + "=\"" + escape.Replace(labels[i+1]) + "\"")
+		}
+		out.w.WriteString("}")
+	}
+
+	out.w.WriteString(" " + format(value) + "\n")
+}
+
+func (out *writer) flush() error { return out.w.Flush() }
+
+var escape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func format(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package prometheus_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/prometheus"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+type checker struct {
+	calls  int
+	region *types.StatusRegion
+	err    error
+}
+
+func (c *checker) CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error) {
+	c.calls++
+	return c.region, c.err
+}
+
+func region() *types.StatusRegion {
+	rate := 95.5
+	status := []types.Status{
+		{
+			Code:        types.STATUS_CODE_SUCCESS,
+			Rule:        types.Rule{ID: "C1", Unit: "%", About: "cpu utilization"},
+			SuccessRate: &rate,
+			SoftMM:      &types.MinMax{Min: 1, Avg: 2.5, Max: 40},
+		},
+		{
+			Code: types.STATUS_CODE_FAILURE,
+			Rule: types.Rule{ID: "D3", Unit: "ms", About: "storage i/o latency"},
+		},
+	}
+
+	return &types.StatusRegion{
+		Status: types.STATUS_CODE_FAILURE,
+		Clusters: []types.StatusCluster{
+			{
+				Status:  types.STATUS_CODE_SUCCESS,
+				Cluster: &types.Cluster{ID: "cluster"},
+				Writer: []types.StatusNode{
+					{Status: types.STATUS_CODE_SUCCESS, Node: &types.Node{Name: "writer", Type: "db.r5.large"}, Checks: status[:1]},
+				},
+				Checks: []types.Status{
+					{Code: types.STATUS_CODE_UNKNOWN, Rule: types.Rule{ID: "R4", About: "members in single zone"}},
+				},
+			},
+		},
+		Nodes: []types.StatusNode{
+			{
+				Status: types.STATUS_CODE_FAILURE,
+				Node:   &types.Node{Name: "db", Type: "db.m5.large", Engine: &types.Engine{ID: "postgres", Version: "14.7"}},
+				Checks: status,
+			},
+		},
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	exporter := prometheus.New(&checker{region: region()}, types.Filter{}, time.Hour)
+	if err := exporter.Refresh(context.Background()); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	w := httptest.NewRecorder()
+	exporter.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		`rds_health_up 1`,
+		`rds_health_refresh_total 1`,
+		`rds_health_region_status 3`,
+		`rds_health_cluster_status{cluster="cluster"} 1`,
+		`rds_health_node_info{node="db",cluster="",role="instance",class="db.m5.large",engine="postgres",version="14.7"} 1`,
+		`rds_health_node_status{node="writer",cluster="cluster",role="writer"} 1`,
+		`rds_health_node_score{node="db",cluster="",role="instance"} 0.5`,
+		`rds_health_rule_status{node="db",cluster="",role="instance",rule="D3",about="storage i/o latency",unit="ms"} 3`,
+		`rds_health_rule_status{node="",cluster="cluster",role="cluster",rule="R4",about="members in single zone",unit=""} 0`,
+		`rds_health_rule_success_rate{node="db",cluster="",role="instance",rule="C1",about="cpu utilization",unit="%"} 95.5`,
+		`rds_health_rule_value{node="db",cluster="",role="instance",rule="C1",about="cpu utilization",unit="%",stat="max"} 40`,
+		"# TYPE rds_health_rule_value gauge\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("should contain %s", expected)
+		}
+	}
+
+	if strings.Contains(body, `rule="D3",about="storage i/o latency",unit="ms",stat=`) {
+		t.Errorf("should not report values of rules without observations")
+	}
+}
+
+func TestRefreshFailure(t *testing.T) {
+	api := &checker{region: region()}
+	exporter := prometheus.New(api, types.Filter{}, time.Hour)
+	exporter.Refresh(context.Background())
+
+	api.region, api.err = nil, errors.New("throttled")
+	if err := exporter.Refresh(context.Background()); err == nil {
+		t.Errorf("should fail with error")
+	}
+
+	snapshot := exporter.Snapshot()
+	if snapshot.Region == nil || snapshot.Refresh != 2 || snapshot.Failures != 1 {
+		t.Errorf("should keep previous status |%+v|", snapshot)
+	}
+
+	buf := &strings.Builder{}
+	prometheus.Write(buf, snapshot)
+	if !strings.Contains(buf.String(), "rds_health_up 0\n") {
+		t.Errorf("should report failure of refresh")
+	}
+}
+
+func TestScrapeFromSnapshot(t *testing.T) {
+	api := &checker{region: region()}
+	exporter := prometheus.New(api, types.Filter{}, time.Hour)
+	exporter.Refresh(context.Background())
+
+	for i := 0; i < 3; i++ {
+		exporter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics", nil))
+	}
+
+	if api.calls != 1 {
+		t.Errorf("scrapes should not check the region |%d|", api.calls)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	r := region()
+	r.Nodes[0].Node.Name = `a"b\c`
+
+	buf := &strings.Builder{}
+	prometheus.Write(buf, prometheus.Snapshot{Region: r})
+	if !strings.Contains(buf.String(), `node="a\"b\\c"`) {
+		t.Errorf("should escape label values")
+	}
+}
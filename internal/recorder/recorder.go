@@ -0,0 +1,271 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/pi"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+	"github.com/zalando/rds-health/internal/cluster"
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/insight"
+	"github.com/zalando/rds-health/internal/instance"
+)
+
+//
+// The package records AWS API traffic made through Provider interfaces
+// and replays it back. The recording is JSON lines file, one request and
+// its response (or error) per line.
+//
+
+// Interaction with AWS API
+type Interaction struct {
+	API      string          `json:"api"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Code     string          `json:"code,omitempty"`    // code of AWS API error
+	Message  string          `json:"message,omitempty"` // message of AWS API error
+}
+
+// key of the interaction
+func (i Interaction) key() string { return i.API + " " + string(i.Request) }
+
+// interceptor of API calls, call executes the request and assigns the
+// response to the resp, replay decodes recorded response into resp.
+type interceptor interface {
+	intercept(api string, req any, call func() error, resp any) error
+}
+
+func through[T any](via interceptor, api string, req any, call func() (T, error)) (T, error) {
+	var resp T
+	err := via.intercept(api, req,
+		func() (err error) {
+			resp, err = call()
+			return err
+		},
+		&resp,
+	)
+	return resp, err
+}
+
+// The window of Performance Insights metrics is defined by current time,
+// requests are identified by duration of the window.
+func metricsRequest(req *pi.GetResourceMetricsInput) any {
+	key := *req
+	key.StartTime, key.EndTime = nil, nil
+
+	return struct {
+		Request *pi.GetResourceMetricsInput
+		Window  time.Duration
+	}{&key, aws.ToTime(req.EndTime).Sub(aws.ToTime(req.StartTime)).Round(time.Minute)}
+}
+
+//
+// Providers
+//
+
+type providers struct {
+	via interceptor
+	rds interface {
+		database.Provider
+		cluster.Provider
+	}
+	ec2 instance.Provider
+	pi  insight.Provider
+}
+
+func (p *providers) DescribeDBInstances(ctx context.Context, req *rds.DescribeDBInstancesInput, opts ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	return through(p.via, "rds:DescribeDBInstances", req, func() (*rds.DescribeDBInstancesOutput, error) {
+		return p.rds.DescribeDBInstances(ctx, req, opts...)
+	})
+}
+
+func (p *providers) DescribeDBClusters(ctx context.Context, req *rds.DescribeDBClustersInput, opts ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return through(p.via, "rds:DescribeDBClusters", req, func() (*rds.DescribeDBClustersOutput, error) {
+		return p.rds.DescribeDBClusters(ctx, req, opts...)
+	})
+}
+
+func (p *providers) DescribePendingMaintenanceActions(ctx context.Context, req *rds.DescribePendingMaintenanceActionsInput, opts ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	return through(p.via, "rds:DescribePendingMaintenanceActions", req, func() (*rds.DescribePendingMaintenanceActionsOutput, error) {
+		return p.rds.DescribePendingMaintenanceActions(ctx, req, opts...)
+	})
+}
+
+func (p *providers) DescribeInstanceTypes(ctx context.Context, req *ec2.DescribeInstanceTypesInput, opts ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	return through(p.via, "ec2:DescribeInstanceTypes", req, func() (*ec2.DescribeInstanceTypesOutput, error) {
+		return p.ec2.DescribeInstanceTypes(ctx, req, opts...)
+	})
+}
+
+func (p *providers) GetResourceMetrics(ctx context.Context, req *pi.GetResourceMetricsInput, opts ...func(*pi.Options)) (*pi.GetResourceMetricsOutput, error) {
+	return through(p.via, "pi:GetResourceMetrics", metricsRequest(req), func() (*pi.GetResourceMetricsOutput, error) {
+		return p.pi.GetResourceMetrics(ctx, req, opts...)
+	})
+}
+
+//
+// Recorder
+//
+
+// Recorder captures requests and responses of AWS APIs. Interactions are
+// written as soon as response is received.
+type Recorder struct {
+	providers
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func New(
+	w io.Writer,
+	rds interface {
+		database.Provider
+		cluster.Provider
+	},
+	ec2 instance.Provider,
+	pi insight.Provider,
+) *Recorder {
+	r := &Recorder{w: w}
+	r.providers = providers{via: r, rds: rds, ec2: ec2, pi: pi}
+	return r
+}
+
+func (r *Recorder) intercept(api string, req any, call func() error, resp any) error {
+	err := call()
+
+	rec := Interaction{API: api}
+	if rec.Request, rec.Response = encode(req), encode(resp); err != nil {
+		rec.Response = nil
+		rec.Error = err.Error()
+
+		var api smithy.APIError
+		if errors.As(err, &api) {
+			rec.Code, rec.Message = api.ErrorCode(), api.ErrorMessage()
+		}
+	}
+
+	b, _ := json.Marshal(rec)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the recording is best effort, it does not fail the application
+	r.w.Write(append(b, '\n'))
+
+	return err
+}
+
+func encode(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+//
+// Replayer
+//
+
+// Replayer serves recorded responses of AWS APIs. Repeated requests are
+// served in the recorded order, the last response is repeated afterwards.
+type Replayer struct {
+	providers
+	mu     sync.Mutex
+	served map[string]int
+	record map[string][]Interaction
+}
+
+// Load the recording
+func Load(r io.Reader) (*Replayer, error) {
+	rp := &Replayer{
+		served: make(map[string]int),
+		record: make(map[string][]Interaction),
+	}
+	rp.providers = providers{via: rp}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var rec Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid recording: %w", err)
+		}
+
+		// recording might be edited by humans
+		buf := &bytes.Buffer{}
+		if err := json.Compact(buf, rec.Request); err != nil {
+			return nil, fmt.Errorf("invalid recording: %w", err)
+		}
+		rec.Request = buf.Bytes()
+
+		rp.record[rec.key()] = append(rp.record[rec.key()], rec)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rp, nil
+}
+
+func (rp *Replayer) intercept(api string, req any, call func() error, resp any) error {
+	key := Interaction{API: api, Request: encode(req)}.key()
+
+	rp.mu.Lock()
+	seq := rp.record[key]
+	n := rp.served[key]
+	rp.served[key]++
+	rp.mu.Unlock()
+
+	if len(seq) == 0 {
+		return fmt.Errorf("not recorded: %s %s", api, encode(req))
+	}
+
+	rec := seq[min(n, len(seq)-1)]
+	switch {
+	case rec.Code != "":
+		return apiError(rec.Code, rec.Message)
+	case rec.Error != "":
+		return errors.New(rec.Error)
+	}
+
+	return json.Unmarshal(rec.Response, resp)
+}
+
+// rebuilds recorded AWS API error, faults the application checks by type
+// are typed, others are generic.
+func apiError(code, message string) error {
+	switch code {
+	case (*rdstypes.DBInstanceNotFoundFault)(nil).ErrorCode():
+		return &rdstypes.DBInstanceNotFoundFault{Message: &message}
+	case (*rdstypes.DBClusterNotFoundFault)(nil).ErrorCode():
+		return &rdstypes.DBClusterNotFoundFault{Message: &message}
+	default:
+		return &smithy.GenericAPIError{Code: code, Message: message}
+	}
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package recorder_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pi"
+	pitypes "github.com/aws/aws-sdk-go-v2/service/pi/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/insight"
+	"github.com/zalando/rds-health/internal/mocks"
+	"github.com/zalando/rds-health/internal/recorder"
+	"go.uber.org/mock/gomock"
+)
+
+type rdsMock struct {
+	*mocks.Database
+	*mocks.Cluster
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbs := &rds.DescribeDBInstancesOutput{
+		DBInstances: []rdstypes.DBInstance{
+			{
+				DBInstanceIdentifier: aws.String("test-db"),
+				DBInstanceClass:      aws.String("db.m5.large"),
+				Engine:               aws.String("postgres"),
+			},
+		},
+	}
+
+	mdb := mocks.NewDatabase(ctrl)
+	mdb.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Return(dbs, nil).Times(1)
+	mdb.EXPECT().DescribePendingMaintenanceActions(gomock.Any(), gomock.Any()).Return(nil, errors.New("access denied")).Times(1)
+
+	t0 := time.Now()
+	metrics := &pi.GetResourceMetricsOutput{
+		MetricList: []pitypes.MetricKeyDataPoints{
+			{
+				Key: &pitypes.ResponseResourceMetricKey{Metric: aws.String("db.load.avg")},
+				DataPoints: []pitypes.DataPoint{
+					{Timestamp: aws.Time(t0), Value: aws.Float64(1.0)},
+					{Timestamp: aws.Time(t0.Add(time.Minute)), Value: aws.Float64(2.0)},
+				},
+			},
+		},
+	}
+
+	mpi := mocks.NewInsight(ctrl)
+	mpi.EXPECT().GetResourceMetrics(gomock.Any(), gomock.Any()).Return(metrics, nil).Times(1)
+
+	//
+	buf := &bytes.Buffer{}
+	rec := recorder.New(buf, rdsMock{Database: mdb, Cluster: mocks.NewCluster(ctrl)}, mocks.NewInstance(ctrl), mpi)
+
+	if _, err := database.New(rec).Lookup(context.TODO(), "test-db"); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+	if _, err := database.New(rec).LookupMaintenance(context.TODO()); err == nil {
+		t.Fatalf("should fail with error")
+	}
+	if _, err := insight.New(rec).Fetch(context.TODO(), "db-ABC", time.Hour, "db.load.avg"); err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	//
+	rp, err := recorder.Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		node, err := database.New(rp).Lookup(context.TODO(), "test-db")
+		switch {
+		case err != nil:
+			t.Errorf("should not fail with error %s", err)
+		case node.Name != "test-db" || node.Type != "db.m5.large":
+			t.Errorf("should replay response |%+v|", node)
+		}
+	}
+
+	if _, err := database.New(rp).LookupMaintenance(context.TODO()); err == nil || err.Error() != "access denied" {
+		t.Errorf("should replay error |%v|", err)
+	}
+
+	// metrics window is shifted by time but has the same duration
+	samples, err := insight.New(rp).Fetch(context.TODO(), "db-ABC", time.Hour, "db.load.avg")
+	switch {
+	case err != nil:
+		t.Errorf("should not fail with error %s", err)
+	case len(samples["db.load.avg"]) != 2:
+		t.Errorf("should replay metrics |%v|", samples)
+	}
+
+	if _, err := database.New(rp).Lookup(context.TODO(), "other-db"); err == nil {
+		t.Errorf("should fail on request that is not recorded")
+	}
+}
+
+func TestReplayAPIError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mdb := mocks.NewDatabase(ctrl)
+	mdb.EXPECT().DescribeDBInstances(gomock.Any(), gomock.Any()).Return(nil, &rdstypes.DBInstanceNotFoundFault{Message: aws.String("gone")}).Times(1)
+	mdb.EXPECT().DescribePendingMaintenanceActions(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "AccessDenied", Message: "denied"}).Times(1)
+
+	buf := &bytes.Buffer{}
+	rec := recorder.New(buf, rdsMock{Database: mdb, Cluster: mocks.NewCluster(ctrl)}, mocks.NewInstance(ctrl), mocks.NewInsight(ctrl))
+
+	database.New(rec).Lookup(context.TODO(), "test-db")
+	database.New(rec).LookupMaintenance(context.TODO())
+
+	rp, err := recorder.Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	if _, err := database.New(rp).Lookup(context.TODO(), "test-db"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("should replay not found |%v|", err)
+	}
+
+	var api smithy.APIError
+	_, err = database.New(rp).LookupMaintenance(context.TODO())
+	switch {
+	case !errors.As(err, &api):
+		t.Errorf("should replay API error |%v|", err)
+	case api.ErrorCode() != "AccessDenied" || api.ErrorMessage() != "denied":
+		t.Errorf("should replay code and message |%v|", api)
+	}
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rightsize
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/zalando/rds-health/internal/catalog"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// Usage of compute resources by the workload, NaN if metric is not observed
+type Usage struct {
+	CPU    float64 // cpu utilization, 95th percentile of max, %
+	Load   float64 // db load, 95th percentile of average active sessions
+	Memory float64 // memory used by processes, excluding free and file system cache, GiB
+}
+
+// Target utilization of the instance class
+const (
+	// aligned with the threshold of health check (C1)
+	TARGET_CPU = 60.0
+	// active sessions shall not exceed available vCPUs
+	TARGET_LOAD = 100.0
+	// half of memory is left for buffers and file system cache
+	TARGET_MEMORY = 50.0
+)
+
+// Rightsize recommends instance class for the node using its usage of
+// compute resources. It recommends the best fit within the current family
+// (keep, downsize or upsize) and the best fit among all families.
+type Rightsize struct {
+	catalog *catalog.Catalog
+}
+
+func New(catalog *catalog.Catalog) *Rightsize {
+	return &Rightsize{catalog: catalog}
+}
+
+// Recommend instance class for the node
+func (rs *Rightsize) Recommend(node types.Node, usage Usage) types.Rightsize {
+	current, has := rs.current(node)
+	if !has {
+		return types.Rightsize{Node: &node}
+	}
+
+	// Demand of the workload in absolute units
+	demand := demand{
+		cpu:    usage.CPU / 100.0 * float64(current.VCPU),
+		load:   usage.Load,
+		memory: usage.Memory,
+	}
+	options := []types.Sizing{rs.sizing(*current, demand, types.SIZING_CURRENT)}
+
+	if demand.unknown() {
+		return types.Rightsize{Node: &node, Options: options}
+	}
+
+	candidates := make([]catalog.Class, 0)
+	for _, c := range rs.catalog.Classes {
+		if c.Burstable() && !current.Burstable() {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	slices.SortStableFunc(candidates, compare)
+
+	// best fit within the family, the largest one if nothing fits
+	var family *catalog.Class
+	for i, c := range candidates {
+		if c.Family() != current.Family() {
+			continue
+		}
+
+		if family == nil || !demand.fits(*family) {
+			family = &candidates[i]
+		}
+
+		if demand.fits(c) {
+			break
+		}
+	}
+
+	if family != nil && family.Class != current.Class {
+		action := types.SIZING_UPSIZE
+		if compare(*family, *current) < 0 {
+			action = types.SIZING_DOWNSIZE
+		}
+		options = append(options, rs.sizing(*family, demand, action))
+	}
+
+	// best fit among all families
+	for _, c := range candidates {
+		if !demand.fits(c) {
+			continue
+		}
+
+		if c.Family() != current.Family() {
+			options = append(options, rs.sizing(c, demand, types.SIZING_CHANGE))
+		}
+		break
+	}
+
+	return types.Rightsize{Node: &node, Options: options}
+}
+
+// current class of the node, compute is taken from node if it is known
+func (rs *Rightsize) current(node types.Node) (*catalog.Class, bool) {
+	class := catalog.Class{Class: node.Type}
+	if c, has := rs.catalog.Lookup(node.Type); has {
+		class = *c
+	}
+
+	if node.Compute != nil && node.Compute.CPU != nil && node.Compute.CPU.Cores > 0 {
+		class.VCPU = node.Compute.CPU.Cores
+	}
+
+	if node.Compute != nil && node.Compute.Memory != nil && node.Compute.Memory.Size > 0 {
+		class.Memory = float64(node.Compute.Memory.Size) / float64(types.GiB)
+	}
+
+	return &class, class.VCPU > 0 && class.Memory > 0
+}
+
+func (rs *Rightsize) sizing(class catalog.Class, demand demand, action string) types.Sizing {
+	return types.Sizing{
+		Class:   class.Class,
+		Action:  action,
+		Compute: class.Compute(),
+		Utilization: []types.Utilization{
+			utilization("cpu", demand.cpu, float64(class.VCPU), TARGET_CPU,
+				"%.2f of %d vCPU busy", demand.cpu, class.VCPU),
+			utilization("load", demand.load, float64(class.VCPU), TARGET_LOAD,
+				"%.2f active sessions on %d vCPU", demand.load, class.VCPU),
+			utilization("memory", demand.memory, float64(class.Memory), TARGET_MEMORY,
+				"%.2f of %g GiB used by processes", demand.memory, class.Memory),
+		},
+	}
+}
+
+func utilization(metric string, demand, capacity, target float64, format string, args ...any) types.Utilization {
+	if math.IsNaN(demand) {
+		return types.Utilization{
+			Metric: metric,
+			Target: target,
+			Reason: "not observed",
+			Fit:    true,
+		}
+	}
+
+	value := demand / capacity * 100.0
+	fit := value <= target
+
+	reason := fmt.Sprintf(format, args...)
+	if fit {
+		reason += fmt.Sprintf(", within target %.0f%%", target)
+	} else {
+		reason += fmt.Sprintf(", exceeds target %.0f%%", target)
+	}
+
+	return types.Utilization{
+		Metric:   metric,
+		Value:    value,
+		Target:   target,
+		Reason:   reason,
+		Fit:      fit,
+		Observed: true,
+	}
+}
+
+// demand of the workload: vCPU, active sessions, GiB
+type demand struct{ cpu, load, memory float64 }
+
+func (d demand) unknown() bool {
+	return math.IsNaN(d.cpu) && math.IsNaN(d.load) && math.IsNaN(d.memory)
+}
+
+func (d demand) fits(c catalog.Class) bool {
+	vcpu := float64(c.VCPU)
+	memory := c.Memory
+
+	return (math.IsNaN(d.cpu) || d.cpu/vcpu*100.0 <= TARGET_CPU) &&
+		(math.IsNaN(d.load) || d.load/vcpu*100.0 <= TARGET_LOAD) &&
+		(math.IsNaN(d.memory) || d.memory/memory*100.0 <= TARGET_MEMORY)
+}
+
+// order classes by capacity, Graviton and newer generations are preferred
+// among classes of same capacity
+func compare(a, b catalog.Class) int {
+	switch {
+	case a.VCPU != b.VCPU:
+		return a.VCPU - b.VCPU
+	case a.Memory != b.Memory:
+		return cmp.Compare(a.Memory, b.Memory)
+	case a.Graviton() != b.Graviton() && a.Graviton():
+		return -1
+	case a.Graviton() != b.Graviton() && b.Graviton():
+		return 1
+	default:
+		return b.Generation() - a.Generation()
+	}
+}
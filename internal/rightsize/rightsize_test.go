@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rightsize_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zalando/rds-health/internal/catalog"
+	"github.com/zalando/rds-health/internal/rightsize"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestRecommend(t *testing.T) {
+	sut := rightsize.New(catalog.Default)
+
+	for _, tt := range []struct {
+		class  string
+		usage  rightsize.Usage
+		expect []string
+	}{
+		// oversized instance, downsize within family and suggest graviton
+		{"db.r5.2xlarge", rightsize.Usage{CPU: 6, Load: 0.8, Memory: 6}, []string{"db.r5.2xlarge", "db.r5.large", "db.r7g.large"}},
+		// overloaded instance
+		{"db.m6g.large", rightsize.Usage{CPU: 90, Load: 3, Memory: math.NaN()}, []string{"db.m6g.large", "db.m6g.xlarge", "db.m7g.xlarge"}},
+		// well-sized graviton instance
+		{"db.m7g.large", rightsize.Usage{CPU: 20, Load: 0.5, Memory: 1}, []string{"db.m7g.large"}},
+		// memory bound instance
+		{"db.m7g.xlarge", rightsize.Usage{CPU: 10, Load: 0.5, Memory: 12}, []string{"db.m7g.xlarge", "db.m7g.2xlarge", "db.x2g.large"}},
+	} {
+		r := sut.Recommend(types.Node{Type: tt.class}, tt.usage)
+
+		classes := make([]string, len(r.Options))
+		for i, o := range r.Options {
+			classes[i] = o.Class
+		}
+
+		if len(classes) != len(tt.expect) {
+			t.Errorf("unexpected options for %s |%v|", tt.class, classes)
+			continue
+		}
+
+		for i := range classes {
+			if classes[i] != tt.expect[i] {
+				t.Errorf("unexpected options for %s |%v|", tt.class, classes)
+				break
+			}
+		}
+	}
+}
+
+func TestRecommendAction(t *testing.T) {
+	sut := rightsize.New(catalog.Default)
+
+	r := sut.Recommend(types.Node{Type: "db.r5.2xlarge"}, rightsize.Usage{CPU: 6, Load: 0.8, Memory: 2})
+	switch {
+	case r.Options[0].Action != types.SIZING_CURRENT:
+		t.Errorf("should show current class first |%s|", r.Options[0].Action)
+	case r.Recommended().Action != types.SIZING_DOWNSIZE:
+		t.Errorf("should recommend downsize |%s|", r.Recommended().Action)
+	case r.Recommended().Utilization[0].Value != 24.0:
+		t.Errorf("should estimate cpu utilization |%f|", r.Recommended().Utilization[0].Value)
+	}
+
+	r = sut.Recommend(types.Node{Type: "db.m7g.large"}, rightsize.Usage{CPU: 20, Load: 0.5, Memory: 1})
+	if r.Recommended().Action != types.SIZING_KEEP {
+		t.Errorf("should keep well-sized instance |%s|", r.Recommended().Action)
+	}
+}
+
+func TestRecommendUnknown(t *testing.T) {
+	sut := rightsize.New(catalog.Default)
+
+	r := sut.Recommend(types.Node{Type: "db.unknown"}, rightsize.Usage{CPU: 20})
+	if len(r.Options) != 0 {
+		t.Errorf("should not recommend for unknown class |%v|", r.Options)
+	}
+
+	compute := &types.Compute{
+		CPU:    &types.CPU{Cores: 2},
+		Memory: &types.Storage{Size: 8 * types.GiB},
+	}
+	r = sut.Recommend(types.Node{Type: "db.unknown", Compute: compute}, rightsize.Usage{CPU: 90, Load: 1, Memory: 1})
+	if r.Recommended().Class != "db.m7g.xlarge" {
+		t.Errorf("should recommend class using compute of node |%s|", r.Recommended().Class)
+	}
+}
@@ -41,6 +41,15 @@ func (cal calculator) apply(lhm, rhm []float64) []float64 {
 	return seq
 }
 
+// raw time series of the calculated metric
+func (cal calculator) series(samples []insight.Samples) *types.Series {
+	return seriesOf(string(cal.lhm)+"/"+string(cal.rhm), samples[1],
+		cal.apply(rawSeq(samples[0]), rawSeq(samples[3])),
+		cal.apply(rawSeq(samples[1]), rawSeq(samples[4])),
+		cal.apply(rawSeq(samples[2]), rawSeq(samples[5])),
+	)
+}
+
 // utility function to show metric values
 func (cal calculator) ShowMinMax() ([]Metric, Eval) {
 	return append(cal.lhm.ToMinMax(), cal.rhm.ToMinMax()...), func(samples ...insight.Samples) types.Status {
@@ -62,6 +71,7 @@ func (cal calculator) ShowMinMax() ([]Metric, Eval) {
 			Interval: t,
 			HardMM:   &minmax,
 			SoftMM:   &softminmax,
+			Series:   cal.series(samples),
 		}
 	}
 }
@@ -94,6 +104,8 @@ func (cal calculator) Below(tAvg, tMax float64) ([]Metric, Eval) {
 			Interval:    t,
 			SuccessRate: &val,
 			SoftMM:      &minmax,
+			Threshold:   &types.Threshold{Op: "below", Avg: tAvg, Peak: tMax},
+			Series:      cal.series(samples),
 		}
 	}
 }
@@ -126,6 +138,8 @@ func (cal calculator) Above(tMin, tAvg float64) ([]Metric, Eval) {
 			Interval:    t,
 			SuccessRate: &val,
 			SoftMM:      &minmax,
+			Threshold:   &types.Threshold{Op: "above", Avg: tAvg, Peak: tMin},
+			Series:      cal.series(samples),
 		}
 	}
 }
@@ -0,0 +1,252 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rules
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// Cluster rules are evaluated across members of the cluster, using the
+// health status of members and the topology of cluster.
+//
+
+// Member of the cluster, the load is soft average of cpu utilization (%)
+// and the traffic is peak of rows fetched per second, NaN if unknown.
+type Member struct {
+	Node    *types.Node
+	Load    float64
+	Traffic float64
+}
+
+// Members of the cluster
+type Members struct {
+	Writer []Member
+	Reader []Member
+}
+
+// ClusterRule is evaluated across members of the cluster. It returns
+// status and observed value.
+type ClusterRule struct {
+	ID    string
+	Unit  string
+	About string
+	Eval  func(Members) (types.StatusCode, string)
+}
+
+// Cluster rules, the balance of writer and readers
+var (
+	ClusterLoadBalance = ClusterRule{
+		ID:    "R1",
+		Unit:  "%",
+		About: "reader vs writer load",
+		Eval: func(m Members) (types.StatusCode, string) {
+			if len(m.Writer) == 0 || len(m.Reader) == 0 {
+				return types.STATUS_CODE_UNKNOWN, "no readers"
+			}
+
+			writer, reader := loadOf(m.Writer), loadOf(m.Reader)
+			if math.IsNaN(writer) || math.IsNaN(reader) {
+				return types.STATUS_CODE_UNKNOWN, "-"
+			}
+
+			observed := fmt.Sprintf("writer %.1f%%, readers %.1f%%", writer, reader)
+
+			// imbalance of idle cluster is not significant
+			peak := math.Max(writer, reader)
+			if peak < 10.0 {
+				return types.STATUS_CODE_SUCCESS, observed
+			}
+
+			switch imbalance := math.Abs(writer-reader) / peak; {
+			case imbalance >= 0.75:
+				return types.STATUS_CODE_FAILURE, observed
+			case imbalance >= 0.5:
+				return types.STATUS_CODE_WARNING, observed
+			default:
+				return types.STATUS_CODE_SUCCESS, observed
+			}
+		},
+	}
+
+	ClusterIdleReader = ClusterRule{
+		ID:    "R2",
+		Unit:  "iops",
+		About: "readers without traffic",
+		Eval: func(m Members) (types.StatusCode, string) {
+			if len(m.Reader) == 0 {
+				return types.STATUS_CODE_UNKNOWN, "no readers"
+			}
+
+			known, idle := 0, make([]string, 0)
+			for _, r := range m.Reader {
+				if math.IsNaN(r.Traffic) {
+					continue
+				}
+
+				known++
+				if r.Traffic == 0 {
+					idle = append(idle, r.Node.Name)
+				}
+			}
+
+			switch {
+			case known == 0:
+				return types.STATUS_CODE_UNKNOWN, "-"
+			case len(idle) != 0:
+				return types.STATUS_CODE_WARNING, "idle " + strings.Join(idle, ", ")
+			default:
+				return types.STATUS_CODE_SUCCESS, fmt.Sprintf("%d readers", known)
+			}
+		},
+	}
+
+	ClusterReaderClass = ClusterRule{
+		ID:    "R3",
+		About: "reader class smaller than writer",
+		Eval: func(m Members) (types.StatusCode, string) {
+			if len(m.Writer) == 0 || len(m.Reader) == 0 {
+				return types.STATUS_CODE_UNKNOWN, "no readers"
+			}
+
+			writer := m.Writer[0].Node
+			if !hasCompute(writer) {
+				return types.STATUS_CODE_UNKNOWN, "-"
+			}
+
+			smaller := make([]string, 0)
+			for _, r := range m.Reader {
+				if !hasCompute(r.Node) {
+					return types.STATUS_CODE_UNKNOWN, "-"
+				}
+
+				if r.Node.Compute.CPU.Cores < writer.Compute.CPU.Cores || r.Node.Compute.Memory.Size < writer.Compute.Memory.Size {
+					smaller = append(smaller, r.Node.Name+" "+r.Node.Type)
+				}
+			}
+
+			observed := fmt.Sprintf("writer %s, readers %s", writer.Type, strings.Join(smaller, ", "))
+			switch {
+			// no reader is able to take over the writer's load on failover
+			case len(smaller) == len(m.Reader):
+				return types.STATUS_CODE_FAILURE, observed
+			case len(smaller) != 0:
+				return types.STATUS_CODE_WARNING, observed
+			default:
+				return types.STATUS_CODE_SUCCESS, writer.Type
+			}
+		},
+	}
+
+	ClusterSingleZone = ClusterRule{
+		ID:    "R4",
+		About: "members in single zone",
+		Eval: func(m Members) (types.StatusCode, string) {
+			// single instance cluster has no members to spread across zones
+			members := slices.Concat(m.Writer, m.Reader)
+			if len(members) < 2 {
+				return types.STATUS_CODE_UNKNOWN, "single member"
+			}
+
+			zones := make([]string, 0)
+			for _, x := range members {
+				for _, az := range x.Node.Zones {
+					if !slices.Contains(zones, az) {
+						zones = append(zones, az)
+					}
+				}
+			}
+
+			switch len(zones) {
+			case 0:
+				return types.STATUS_CODE_UNKNOWN, "-"
+			case 1:
+				return types.STATUS_CODE_FAILURE, zones[0]
+			default:
+				return types.STATUS_CODE_SUCCESS, fmt.Sprintf("%d zones", len(zones))
+			}
+		},
+	}
+)
+
+// ClusterRules evaluated by health check
+var ClusterRules = []ClusterRule{
+	ClusterLoadBalance,
+	ClusterIdleReader,
+	ClusterReaderClass,
+	ClusterSingleZone,
+}
+
+// Cluster evaluates cluster rules over health status of its members, the
+// traffic of readers is peak of rows fetched per second by node name. The
+// status code of cluster accounts both members and cluster rules.
+func Cluster(status types.StatusCluster, traffic map[string]float64) types.StatusCluster {
+	member := func(n types.StatusNode) Member {
+		m := Member{Node: n.Node, Load: math.NaN(), Traffic: math.NaN()}
+		if v, has := traffic[n.Node.Name]; has {
+			m.Traffic = v
+		}
+
+		at := slices.IndexFunc(n.Checks, func(s types.Status) bool { return s.Rule.ID == OsCpuUtil.id })
+		if at != -1 && n.Checks[at].SoftMM != nil {
+			m.Load = n.Checks[at].SoftMM.Avg
+		}
+		return m
+	}
+
+	members := Members{
+		Writer: make([]Member, len(status.Writer)),
+		Reader: make([]Member, len(status.Reader)),
+	}
+	for i, n := range status.Writer {
+		members.Writer[i] = member(n)
+	}
+	for i, n := range status.Reader {
+		members.Reader[i] = member(n)
+	}
+
+	status.Checks = make([]types.Status, len(ClusterRules))
+	for i, rule := range ClusterRules {
+		code, observed := rule.Eval(members)
+		status.Checks[i] = types.Status{
+			Code:     code,
+			Rule:     types.Rule{ID: rule.ID, Unit: rule.Unit, About: rule.About},
+			Observed: &observed,
+		}
+		status.Status = max(status.Status, code)
+	}
+
+	return status
+}
+
+// average load of members, members with unknown load are skipped
+func loadOf(seq []Member) float64 {
+	sum, n := 0.0, 0
+	for _, m := range seq {
+		if !math.IsNaN(m.Load) {
+			sum += m.Load
+			n++
+		}
+	}
+
+	if n == 0 {
+		return math.NaN()
+	}
+
+	return sum / float64(n)
+}
+
+func hasCompute(node *types.Node) bool {
+	return node.Compute != nil && node.Compute.CPU != nil && node.Compute.Memory != nil
+}
@@ -0,0 +1,127 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/zalando/rds-health/internal/rules"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func member(name, class string, cores int, zone string, load float64) types.StatusNode {
+	return types.StatusNode{
+		Status: types.STATUS_CODE_SUCCESS,
+		Node: &types.Node{
+			Name:  name,
+			Type:  class,
+			Zones: types.AvailabilityZones{zone},
+			Compute: &types.Compute{
+				CPU:    &types.CPU{Cores: cores},
+				Memory: &types.Storage{Type: "memory", Size: types.BiB(cores) * 8 * types.GiB},
+			},
+		},
+		Checks: []types.Status{
+			{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1"}, SoftMM: &types.MinMax{Avg: load}},
+		},
+	}
+}
+
+func TestCluster(t *testing.T) {
+	status := types.StatusCluster{
+		Status:  types.STATUS_CODE_SUCCESS,
+		Cluster: &types.Cluster{ID: "c"},
+		Writer:  []types.StatusNode{member("w", "db.r6g.xlarge", 4, "eu-central-1a", 40.0)},
+		Reader: []types.StatusNode{
+			member("a", "db.r6g.large", 2, "eu-central-1a", 5.0),
+			member("b", "db.r6g.xlarge", 4, "eu-central-1a", 5.0),
+		},
+	}
+
+	status = rules.Cluster(status, map[string]float64{"a": 0.0, "b": 120.0})
+
+	expect := map[string]types.StatusCode{
+		"R1": types.STATUS_CODE_FAILURE,
+		"R2": types.STATUS_CODE_WARNING,
+		"R3": types.STATUS_CODE_WARNING,
+		"R4": types.STATUS_CODE_FAILURE,
+	}
+
+	if len(status.Checks) != len(expect) {
+		t.Fatalf("unexpected checks |%+v|", status.Checks)
+	}
+
+	for _, check := range status.Checks {
+		if check.Code != expect[check.Rule.ID] {
+			t.Errorf("unexpected status %s of %s (%s)", check.Code, check.Rule.ID, *check.Observed)
+		}
+	}
+
+	if status.Status != types.STATUS_CODE_FAILURE {
+		t.Errorf("cluster status should account cluster rules")
+	}
+}
+
+func TestClusterWithoutReaders(t *testing.T) {
+	status := types.StatusCluster{
+		Status:  types.STATUS_CODE_SUCCESS,
+		Cluster: &types.Cluster{ID: "c"},
+		Writer: []types.StatusNode{
+			member("w", "db.r6g.xlarge", 4, "eu-central-1a", 40.0),
+			member("x", "db.r6g.xlarge", 4, "eu-central-1b", 40.0),
+		},
+	}
+
+	status = rules.Cluster(status, nil)
+	for _, check := range status.Checks {
+		switch check.Rule.ID {
+		case "R4":
+			if check.Code != types.STATUS_CODE_SUCCESS {
+				t.Errorf("unexpected status %s of %s", check.Code, check.Rule.ID)
+			}
+		default:
+			if check.Code != types.STATUS_CODE_UNKNOWN {
+				t.Errorf("unexpected status %s of %s", check.Code, check.Rule.ID)
+			}
+		}
+	}
+
+	if status.Status != types.STATUS_CODE_SUCCESS {
+		t.Errorf("unexpected cluster status %s", status.Status)
+	}
+}
+
+func TestClusterSingleMember(t *testing.T) {
+	status := types.StatusCluster{
+		Status:  types.STATUS_CODE_SUCCESS,
+		Cluster: &types.Cluster{ID: "c"},
+		Writer:  []types.StatusNode{member("w", "db.r6g.xlarge", 4, "eu-central-1a", 40.0)},
+	}
+
+	status = rules.Cluster(status, nil)
+	for _, check := range status.Checks {
+		if check.Code != types.STATUS_CODE_UNKNOWN {
+			t.Errorf("unexpected status %s of %s", check.Code, check.Rule.ID)
+		}
+	}
+
+	if status.Status != types.STATUS_CODE_SUCCESS {
+		t.Errorf("unexpected cluster status %s", status.Status)
+	}
+}
+
+func TestProfileCluster(t *testing.T) {
+	match, err := rules.Profile("cluster")
+	switch {
+	case err != nil:
+		t.Fatalf("should not fail with error %s", err)
+	case !match(types.Rule{ID: "R3"}) || match(types.Rule{ID: "C1"}):
+		t.Errorf("unexpected matcher of cluster profile")
+	}
+}
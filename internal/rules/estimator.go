@@ -45,6 +45,7 @@ func (est estimator) ShowMinMax() ([]Metric, Eval) {
 			Interval: t,
 			HardMM:   &minmax,
 			SoftMM:   &softminmax,
+			Series:   seriesOf(string(est.name), samples[1], rawSeq(samples[0]), rawSeq(samples[1]), rawSeq(samples[2])),
 		}
 	}
 }
@@ -87,6 +88,8 @@ func (est estimator) Below(tAvg, tMax float64) ([]Metric, Eval) {
 			Interval:    t,
 			SuccessRate: &val,
 			SoftMM:      &minmax,
+			Threshold:   &types.Threshold{Op: "below", Avg: tAvg, Peak: tMax},
+			Series:      seriesOf(string(est.name), samples[1], rawSeq(samples[0]), rawSeq(samples[1]), rawSeq(samples[2])),
 		}
 	}
 }
@@ -113,6 +116,8 @@ func (est estimator) Above(tMin, tAvg float64) ([]Metric, Eval) {
 			Interval:    t,
 			SuccessRate: &val,
 			SoftMM:      &minmax,
+			Threshold:   &types.Threshold{Op: "above", Avg: tAvg, Peak: tMin},
+			Series:      seriesOf(string(est.name), samples[1], rawSeq(samples[0]), rawSeq(samples[1]), rawSeq(samples[2])),
 		}
 	}
 }
@@ -237,6 +237,16 @@ var (
 		`,
 	}
 
+	DbLoad = estimator{
+		name: "db.load",
+		unit: "aas",
+		info: "db load (average active sessions)",
+		desc: `
+			Number of sessions concurrently active in the database.
+			The database is overloaded if the value exceeds the number of vCPUs.
+		`,
+	}
+
 	SqlTuplesFetched = estimator{
 		name: "db.SQL.tup_fetched",
 		unit: "iops",
@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// Profiles of health rules, each profile focuses on the single resource
+var Profiles = map[string][]string{
+	"cpu":      {"C1", "C2"},
+	"memory":   {"M1", "M2", "P1"},
+	"storage":  {"D1", "D2", "D3", "P2"},
+	"database": {"P1", "P2", "P3", "P4", "P5"},
+	"cluster":  {"R1", "R2", "R3", "R4"},
+}
+
+// Profile decodes the profile name or comma separated list of rule ids
+// (e.g. C1,D3) into the matcher of rules. Empty profile or "all" matches
+// all rules.
+func Profile(profile string) (func(types.Rule) bool, error) {
+	if profile == "" || profile == "all" {
+		return func(types.Rule) bool { return true }, nil
+	}
+
+	ids := make([]string, 0)
+	for _, id := range strings.Split(profile, ",") {
+		id = strings.TrimSpace(id)
+		switch {
+		case Profiles[id] != nil:
+			ids = append(ids, Profiles[id]...)
+		case slices.Contains(RuleIDs(), id):
+			ids = append(ids, id)
+		default:
+			return nil, fmt.Errorf("rule profile %s is not supported, use cpu, memory, storage, database, cluster, all or rule ids (%s)", id, strings.Join(RuleIDs(), ", "))
+		}
+	}
+
+	return func(rule types.Rule) bool { return slices.Contains(ids, rule.ID) }, nil
+}
+
+// RuleIDs of health rules covered by profiles, sorted
+func RuleIDs() []string {
+	ids := make([]string, 0)
+	for _, seq := range Profiles {
+		for _, id := range seq {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	slices.Sort(ids)
+	return ids
+}
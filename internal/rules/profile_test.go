@@ -0,0 +1,27 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/zalando/rds-health/internal/rules"
+)
+
+func TestProfileRuleIDs(t *testing.T) {
+	if _, err := rules.Profile("C1,D3,R4"); err != nil {
+		t.Errorf("should not fail with error %s", err)
+	}
+
+	for _, profile := range []string{"C9", "X1", "D3,P6"} {
+		if _, err := rules.Profile(profile); err == nil {
+			t.Errorf("should fail on unknown rule %s", profile)
+		}
+	}
+}
@@ -11,6 +11,7 @@ package rules
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/zalando/rds-health/internal/insight"
@@ -44,7 +45,7 @@ func (m Metric) ToMinMax() []Metric {
 //
 // `.sum` - The sum of the metric values over a period of time.
 //
-// `.sample_count` - The number of times the metric was collected over a period of time. Append  to the metric name.
+// `.sample_count` - The number of times the metric was collected over a period of time.
 //
 // See https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_PerfInsights.API.html
 type Aggregator string
@@ -54,8 +55,42 @@ const (
 	STATS_AVG = Aggregator("avg")
 	STATS_MIN = Aggregator("min")
 	STATS_MAX = Aggregator("max")
+	STATS_CNT = Aggregator("sample_count")
 )
 
+// Aggregators supported by the telemetry system
+var Aggregators = []Aggregator{STATS_AVG, STATS_MIN, STATS_MAX, STATS_SUM, STATS_CNT}
+
+// raw values of samples, missing values are NaN
+func rawSeq(samples insight.Samples) []float64 {
+	seq := make([]float64, len(samples))
+	for i, val := range samples {
+		seq[i] = math.NaN()
+		if val.Has() {
+			seq[i] = val.X()
+		}
+	}
+	return seq
+}
+
+// raw time series of the rule, timestamps are defined by samples
+func seriesOf(metric string, samples insight.Samples, lo, avg, hi []float64) *types.Series {
+	n := min(len(samples), len(lo), len(avg), len(hi))
+	series := &types.Series{
+		Metric: metric,
+		Time:   make([]time.Time, n),
+		Min:    lo[:n],
+		Avg:    avg[:n],
+		Max:    hi[:n],
+	}
+
+	for i := 0; i < n; i++ {
+		series.Time[i] = samples[i].T()
+	}
+
+	return series
+}
+
 type Eval func(...insight.Samples) types.Status
 type Rule func() ([]Metric, Eval)
 
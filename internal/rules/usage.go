@@ -0,0 +1,160 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rules
+
+import (
+	"math"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// Usage is the set of metrics showing resource utilization of instances,
+// clusters and regions.
+var Usage = []Rule{
+	DbXactCommit.ShowMinMax,
+	SqlTuplesFetched.ShowMinMax,
+	SqlTuplesReturned.ShowMinMax,
+	SqlTuplesInserted.ShowMinMax,
+	SqlTuplesUpdated.ShowMinMax,
+	SqlTuplesDeleted.ShowMinMax,
+	OsCpuUtil.ShowMinMax,
+	OsCpuWait.ShowMinMax,
+	DbStorageReadIO.ShowMinMax,
+	DbStorageWriteIO.ShowMinMax,
+	DbDataBlockReadIO.ShowMinMax,
+	DbDataBlockCacheHit.ShowMinMax,
+	DbBuffersCheckpoints.ShowMinMax,
+	DbBuffersCheckpointsTime.ShowMinMax,
+	OsMemoryFree.ShowMinMax,
+	OsMemoryCached.ShowMinMax,
+	OsFileSysUsed.ShowMinMax,
+}
+
+// NewUsage creates the check of resource utilization, it is limited to
+// given metrics in the given order if any (see UsageMetrics).
+func NewUsage(source Source, metrics ...string) *Check {
+	check := New(source)
+	if len(metrics) == 0 {
+		for _, rule := range Usage {
+			check.Should(rule())
+		}
+		return check
+	}
+
+	for _, metric := range metrics {
+		for _, rule := range Usage {
+			if usageMetric(rule) == metric {
+				check.Should(rule())
+			}
+		}
+	}
+	return check
+}
+
+// UsageMetrics are names of metrics of resource utilization
+func UsageMetrics() []string {
+	seq := make([]string, len(Usage))
+	for i, rule := range Usage {
+		seq[i] = usageMetric(rule)
+	}
+	return seq
+}
+
+func usageMetric(rule Rule) string {
+	metrics, _ := rule()
+	return strings.TrimSuffix(string(metrics[0]), ".min")
+}
+
+// Totals of resource utilization across nodes (e.g. members of cluster).
+// Metrics measured in percents are averaged, other metrics are summed up.
+// Nodes without data are skipped.
+func Totals(nodes []types.StatusNode) []types.Status {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	seq := make([]types.Status, len(nodes[0].Checks))
+	for i, s := range nodes[0].Checks {
+		hard, soft := make([]types.MinMax, 0, len(nodes)), make([]types.MinMax, 0, len(nodes))
+		for _, node := range nodes {
+			for _, x := range node.Checks {
+				if x.Rule != s.Rule {
+					continue
+				}
+				if x.HardMM != nil {
+					hard = append(hard, *x.HardMM)
+				}
+				if x.SoftMM != nil {
+					soft = append(soft, *x.SoftMM)
+				}
+			}
+		}
+
+		agg := sumOf
+		if s.Rule.Unit == "%" {
+			agg = avgOf
+		}
+
+		seq[i] = types.Status{
+			Code:     types.STATUS_CODE_UNKNOWN,
+			Rule:     s.Rule,
+			Interval: s.Interval,
+			HardMM:   totalOf(hard, agg),
+			SoftMM:   totalOf(soft, agg),
+		}
+
+		// totals keep name of metric but not its samples
+		if s.Series != nil {
+			seq[i].Series = &types.Series{Metric: s.Series.Metric}
+		}
+	}
+
+	return seq
+}
+
+func totalOf(seq []types.MinMax, agg func([]float64) float64) *types.MinMax {
+	lo, avg, hi := make([]float64, len(seq)), make([]float64, len(seq)), make([]float64, len(seq))
+	for i, mm := range seq {
+		lo[i], avg[i], hi[i] = mm.Min, mm.Avg, mm.Max
+	}
+
+	return &types.MinMax{Min: agg(lo), Avg: agg(avg), Max: agg(hi)}
+}
+
+// sum of values, NaN are skipped, NaN if no values
+func sumOf(seq []float64) float64 {
+	sum, n := 0.0, 0
+	for _, x := range seq {
+		if !math.IsNaN(x) {
+			sum += x
+			n++
+		}
+	}
+
+	if n == 0 {
+		return math.NaN()
+	}
+	return sum
+}
+
+// average of values, NaN are skipped, NaN if no values
+func avgOf(seq []float64) float64 {
+	n := 0
+	for _, x := range seq {
+		if !math.IsNaN(x) {
+			n++
+		}
+	}
+
+	if n == 0 {
+		return math.NaN()
+	}
+	return sumOf(seq) / float64(n)
+}
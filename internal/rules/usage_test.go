@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/insight"
+	"github.com/zalando/rds-health/internal/rules"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func usage(name string, tps, cpu float64) types.StatusNode {
+	return types.StatusNode{
+		Node: &types.Node{Name: name},
+		Checks: []types.Status{
+			{Rule: types.Rule{Unit: "tps", About: "db transactions"}, SoftMM: &types.MinMax{Min: tps, Avg: tps, Max: tps}},
+			{Rule: types.Rule{Unit: "%", About: "cpu utilization"}, SoftMM: &types.MinMax{Min: cpu, Avg: cpu, Max: cpu}},
+		},
+	}
+}
+
+func TestTotals(t *testing.T) {
+	seq := rules.Totals([]types.StatusNode{
+		usage("a", 10.0, 60.0),
+		usage("b", 20.0, 20.0),
+		usage("c", math.NaN(), math.NaN()),
+	})
+
+	switch {
+	case len(seq) != 2:
+		t.Fatalf("unexpected totals |%+v|", seq)
+	case seq[0].SoftMM.Avg != 30.0:
+		t.Errorf("should sum up transactions |%+v|", seq[0].SoftMM)
+	case seq[1].SoftMM.Avg != 40.0:
+		t.Errorf("should average cpu utilization |%+v|", seq[1].SoftMM)
+	case seq[0].HardMM == nil || !math.IsNaN(seq[0].HardMM.Avg):
+		t.Errorf("should be NaN without data |%+v|", seq[0].HardMM)
+	}
+
+	if rules.Totals(nil) != nil {
+		t.Errorf("should be empty for no nodes")
+	}
+}
+
+func TestUsageMetrics(t *testing.T) {
+	seq := rules.UsageMetrics()
+	switch {
+	case len(seq) != len(rules.Usage):
+		t.Errorf("unexpected metrics |%v|", seq)
+	case !slices.Contains(seq, "os.cpuUtilization.total"):
+		t.Errorf("should contain cpu utilization |%v|", seq)
+	}
+}
+
+// source of samples, which records requested metrics and fails
+type source []string
+
+func (s *source) Fetch(_ context.Context, _ string, _ time.Duration, metrics ...string) (map[string]insight.Samples, error) {
+	*s = append(*s, metrics...)
+	return nil, errors.New("no samples")
+}
+
+func TestNewUsageMetrics(t *testing.T) {
+	for _, tt := range []struct {
+		metrics []string
+		expect  []string
+	}{
+		{metrics: nil, expect: rules.UsageMetrics()},
+		{metrics: []string{"os.memory.free", "os.cpuUtilization.total"}, expect: []string{"os.memory.free", "os.cpuUtilization.total"}},
+		{metrics: []string{"os.unknown"}, expect: []string{}},
+	} {
+		var fetched source
+		if _, err := rules.NewUsage(&fetched, tt.metrics...).Run(context.Background(), "db", time.Hour); err == nil {
+			t.Errorf("should fail")
+		}
+
+		roots := make([]string, 0)
+		for _, metric := range fetched {
+			if root, has := strings.CutSuffix(metric, ".min"); has {
+				roots = append(roots, root)
+			}
+		}
+
+		if !slices.Equal(roots, tt.expect) {
+			t.Errorf("unexpected metrics %v: fetched |%v|", tt.metrics, fetched)
+		}
+	}
+}
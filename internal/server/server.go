@@ -0,0 +1,246 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/zalando/rds-health/internal/cache"
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/rules"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package exposes the service as HTTP JSON API, the endpoints mirror
+// commands of the application:
+//
+//	GET /v1/check          health status of the region (types.StatusRegion)
+//	GET /v1/check/{name}   health status of the instance (types.StatusNode)
+//	GET /v1/list           instances and clusters of the region (types.Region)
+//	GET /v1/show/{name}    resource utilization of the instance (types.StatusNode)
+//	GET /healthz           liveness of the server
+//	GET /readyz            readiness of the server to serve requests
+//
+// Query parameters are
+//
+//	interval    time interval (e.g. 7d), defaults to 24h
+//	profile     rule profile (cpu, memory, storage, database, cluster) or rule ids
+//	tag, where, engine, class, cluster, name, name-regex
+//	            filters of instances, same as command line flags
+//
+
+// Service used by the server
+type Service interface {
+	CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error)
+	CheckHealthNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error)
+	ShowRegion(ctx context.Context, filter types.Filter) (*types.Region, error)
+	ShowNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error)
+}
+
+// Server of HTTP JSON API
+type Server struct {
+	*http.ServeMux
+	api   Service
+	ready func(context.Context) error
+}
+
+// New server, the ready function probes the readiness of the server
+// (e.g. availability of AWS credentials).
+func New(api Service, ready func(context.Context) error) *Server {
+	s := &Server{
+		ServeMux: http.NewServeMux(),
+		api:      api,
+		ready:    ready,
+	}
+
+	s.HandleFunc("GET /v1/check", s.checkHealthRegion)
+	s.HandleFunc("GET /v1/check/{name}", s.checkHealthNode)
+	s.HandleFunc("GET /v1/list", s.showRegion)
+	s.HandleFunc("GET /v1/show/{name}", s.showNode)
+	s.HandleFunc("GET /healthz", s.healthz)
+	s.HandleFunc("GET /readyz", s.readyz)
+
+	return s
+}
+
+func (s *Server) checkHealthRegion(w http.ResponseWriter, r *http.Request) {
+	q, err := query(r)
+	if err != nil {
+		failure(w, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := s.api.CheckHealthRegion(r.Context(), q.filter, q.interval)
+	if err != nil {
+		failure(w, statusOf(err), err)
+		return
+	}
+
+	success(w, status.WithRules(q.profile))
+}
+
+func (s *Server) checkHealthNode(w http.ResponseWriter, r *http.Request) {
+	q, err := query(r)
+	if err != nil {
+		failure(w, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := s.api.CheckHealthNode(r.Context(), r.PathValue("name"), q.interval)
+	if err != nil {
+		failure(w, statusOf(err), err)
+		return
+	}
+
+	success(w, status.WithRules(q.profile))
+}
+
+func (s *Server) showRegion(w http.ResponseWriter, r *http.Request) {
+	q, err := query(r)
+	if err != nil {
+		failure(w, http.StatusBadRequest, err)
+		return
+	}
+
+	region, err := s.api.ShowRegion(r.Context(), q.filter)
+	if err != nil {
+		failure(w, statusOf(err), err)
+		return
+	}
+
+	success(w, region)
+}
+
+func (s *Server) showNode(w http.ResponseWriter, r *http.Request) {
+	q, err := query(r)
+	if err != nil {
+		failure(w, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := s.api.ShowNode(r.Context(), r.PathValue("name"), q.interval)
+	if err != nil {
+		failure(w, statusOf(err), err)
+		return
+	}
+
+	success(w, status)
+}
+
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	success(w, map[string]string{"status": "ok"})
+}
+
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	if s.ready != nil {
+		if err := s.ready(r.Context()); err != nil {
+			failure(w, http.StatusServiceUnavailable, err)
+			return
+		}
+	}
+
+	success(w, map[string]string{"status": "ready"})
+}
+
+//
+// utils
+//
+
+type params struct {
+	interval time.Duration
+	filter   types.Filter
+	profile  func(types.Rule) bool
+}
+
+func query(r *http.Request) (params, error) {
+	q := r.URL.Query()
+	p := params{interval: 24 * time.Hour}
+
+	var err error
+
+	if v := q.Get("interval"); v != "" {
+		if p.interval, err = types.ParseInterval(v); err != nil {
+			return p, fmt.Errorf("invalid interval: %w", err)
+		}
+	}
+
+	if p.profile, err = rules.Profile(q.Get("profile")); err != nil {
+		return p, err
+	}
+
+	p.filter = types.Filter{
+		Engines:  q["engine"],
+		Classes:  q["class"],
+		Clusters: q["cluster"],
+	}
+
+	if p.filter.Names, err = types.ParseNames(q["name"]); err != nil {
+		return p, err
+	}
+
+	if p.filter.Tags, err = types.ParseTags(q["tag"]); err != nil {
+		return p, err
+	}
+
+	if p.filter.Attributes, err = types.ParseAttributes(q["where"]); err != nil {
+		return p, err
+	}
+
+	if v := q.Get("name-regex"); v != "" {
+		if p.filter.Pattern, err = regexp.Compile(v); err != nil {
+			return p, fmt.Errorf("invalid name regex: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+func statusOf(err error) int {
+	var (
+		dbNotFound      *rdstypes.DBInstanceNotFoundFault
+		clusterNotFound *rdstypes.DBClusterNotFoundFault
+	)
+
+	switch {
+	case errors.Is(err, database.ErrNotFound), errors.Is(err, cache.ErrNotFound), errors.As(err, &dbNotFound), errors.As(err, &clusterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func success(w http.ResponseWriter, val any) {
+	b, err := json.Marshal(val)
+	if err != nil {
+		failure(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+func failure(w http.ResponseWriter, code int, err error) {
+	b, _ := json.Marshal(map[string]string{"error": err.Error()})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(b)
+}
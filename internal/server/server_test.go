@@ -0,0 +1,171 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/server"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+type service struct {
+	name     string
+	filter   types.Filter
+	interval time.Duration
+}
+
+func (s *service) CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error) {
+	s.filter, s.interval = filter, interval
+	return &types.StatusRegion{Status: types.STATUS_CODE_FAILURE, Nodes: []types.StatusNode{node()}}, nil
+}
+
+func (s *service) CheckHealthNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error) {
+	s.name, s.interval = name, interval
+	if name != "db" {
+		return nil, notFound(name)
+	}
+
+	v := node()
+	return &v, nil
+}
+
+func (s *service) ShowRegion(ctx context.Context, filter types.Filter) (*types.Region, error) {
+	s.filter = filter
+	return &types.Region{Nodes: []types.Node{{Name: "db"}}}, nil
+}
+
+func (s *service) ShowNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error) {
+	s.name, s.interval = name, interval
+	if name != "db" {
+		return nil, notFound(name)
+	}
+
+	return nil, errors.New("throttled")
+}
+
+// the error returned by database lookup, it is same with or without cache
+func notFound(name string) error {
+	return fmt.Errorf("%w: rds %s", database.ErrNotFound, name)
+}
+
+func node() types.StatusNode {
+	return types.StatusNode{
+		Status: types.STATUS_CODE_FAILURE,
+		Node:   &types.Node{Name: "db"},
+		Checks: []types.Status{
+			{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1"}, SoftMM: &types.MinMax{}},
+			{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "D3"}, SoftMM: &types.MinMax{}},
+		},
+	}
+}
+
+func get(t *testing.T, api *service, url string, expected int) map[string]any {
+	t.Helper()
+
+	s := server.New(api, func(context.Context) error { return nil })
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+
+	if w.Code != expected {
+		t.Fatalf("unexpected status code of %s |%d| %s", url, w.Code, w.Body)
+	}
+
+	var val map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &val); err != nil {
+		t.Fatalf("should return json %s", err)
+	}
+
+	return val
+}
+
+func TestCheckRegion(t *testing.T) {
+	api := &service{}
+	val := get(t, api, "/v1/check?interval=7d&tag=team=payments&engine=postgres&name-regex=^db", http.StatusOK)
+
+	if api.interval != 7*24*time.Hour {
+		t.Errorf("unexpected interval |%s|", api.interval)
+	}
+
+	if api.filter.Tags["team"] != "payments" || len(api.filter.Engines) != 1 || api.filter.Pattern == nil {
+		t.Errorf("unexpected filter |%+v|", api.filter)
+	}
+
+	if val["Status"] != "failed" {
+		t.Errorf("unexpected status |%v|", val["Status"])
+	}
+}
+
+func TestCheckNodeProfile(t *testing.T) {
+	val := get(t, &service{}, "/v1/check/db?profile=cpu", http.StatusOK)
+
+	if val["code"] != "passed" {
+		t.Errorf("status should be recomputed for the profile |%v|", val["code"])
+	}
+
+	if seq := val["status"].([]any); len(seq) != 1 {
+		t.Errorf("should return rules of the profile only |%v|", seq)
+	}
+}
+
+func TestCheckNodeNotFound(t *testing.T) {
+	get(t, &service{}, "/v1/check/unknown", http.StatusNotFound)
+}
+
+func TestList(t *testing.T) {
+	api := &service{}
+	get(t, api, "/v1/list?class=db.r5.large&class=db.m5.large", http.StatusOK)
+
+	if len(api.filter.Classes) != 2 {
+		t.Errorf("unexpected filter |%+v|", api.filter)
+	}
+}
+
+func TestShowNodeFailure(t *testing.T) {
+	val := get(t, &service{}, "/v1/show/db", http.StatusBadGateway)
+	if val["error"] != "throttled" {
+		t.Errorf("unexpected error |%v|", val["error"])
+	}
+}
+
+func TestShowNodeNotFound(t *testing.T) {
+	get(t, &service{}, "/v1/show/unknown", http.StatusNotFound)
+}
+
+func TestBadRequest(t *testing.T) {
+	for _, url := range []string{
+		"/v1/check?interval=7x",
+		"/v1/check?profile=unknown",
+		"/v1/check?where=unknown=1",
+		"/v1/list?name-regex=(",
+		"/v1/list?name=payment-%5B",
+	} {
+		get(t, &service{}, url, http.StatusBadRequest)
+	}
+}
+
+func TestProbes(t *testing.T) {
+	get(t, &service{}, "/healthz", http.StatusOK)
+	get(t, &service{}, "/readyz", http.StatusOK)
+
+	s := server.New(&service{}, func(context.Context) error { return errors.New("no credentials") })
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("should not be ready |%d|", w.Code)
+	}
+}
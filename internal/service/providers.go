@@ -0,0 +1,202 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/pi"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+	"github.com/zalando/rds-health/internal/cache"
+	"github.com/zalando/rds-health/internal/cluster"
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/insight"
+	"github.com/zalando/rds-health/internal/instance"
+	"github.com/zalando/rds-health/internal/recorder"
+)
+
+// RDS APIs used by the service
+type RDS interface {
+	database.Provider
+	cluster.Provider
+}
+
+// Providers of AWS APIs used by the service
+type Providers struct {
+	RDS RDS
+	EC2 instance.Provider
+	PI  insight.Provider
+}
+
+func NewProviders(conf aws.Config) Providers {
+	return Providers{
+		RDS: rds.NewFromConfig(conf),
+		EC2: ec2.NewFromConfig(conf),
+		PI:  pi.NewFromConfig(conf),
+	}
+}
+
+// WithRecorder records all requests and responses of AWS APIs
+func (p Providers) WithRecorder(w io.Writer) Providers {
+	r := recorder.New(w, p.RDS, p.EC2, p.PI)
+	return Providers{RDS: r, EC2: r, PI: r}
+}
+
+// Replay AWS APIs from the recording, no requests are made to AWS
+func Replay(r io.Reader) (Providers, error) {
+	rp, err := recorder.Load(r)
+	if err != nil {
+		return Providers{}, err
+	}
+
+	return Providers{RDS: rp, EC2: rp, PI: rp}, nil
+}
+
+// WithCache caches responses of AWS APIs in the directory. The cache is
+// isolated per region and credentials, so that accounts never share it.
+func (p Providers) WithCache(conf aws.Config, dir string, ttl time.Duration) (Providers, error) {
+	disk, err := cache.NewDisk(dir)
+	if err != nil {
+		return p, err
+	}
+
+	creds, err := conf.Credentials.Retrieve(context.Background())
+	if err != nil {
+		return p, err
+	}
+
+	hash := sha256.Sum256([]byte(conf.Region + "\n" + creds.AccessKeyID))
+	space := hex.EncodeToString(hash[:8])
+
+	opts := func(api string) []cache.Option {
+		return []cache.Option{cache.WithTTL(ttl), cache.WithDisk(disk, space+"/"+api)}
+	}
+
+	return Providers{
+		RDS: &cachedRDS{
+			RDS:         p.RDS,
+			instances:   cache.New[string, *rds.DescribeDBInstancesOutput](nil, opts("rds:DescribeDBInstances")...),
+			clusters:    cache.New[string, *rds.DescribeDBClustersOutput](nil, opts("rds:DescribeDBClusters")...),
+			maintenance: cache.New[string, *rds.DescribePendingMaintenanceActionsOutput](nil, opts("rds:DescribePendingMaintenanceActions")...),
+		},
+		EC2: &cachedEC2{
+			Provider: p.EC2,
+			types:    cache.New[string, *ec2.DescribeInstanceTypesOutput](nil, opts("ec2:DescribeInstanceTypes")...),
+		},
+		PI: &cachedPI{
+			Provider: p.PI,
+			metrics:  cache.New[string, *pi.GetResourceMetricsOutput](nil, opts("pi:GetResourceMetrics")...),
+		},
+	}, nil
+}
+
+// key of the request
+func keyOf(req any) string {
+	b, _ := json.Marshal(req)
+	return string(b)
+}
+
+// translates "not found" errors of AWS APIs to negative results
+func notFound(err error) error {
+	var (
+		dbNotFound      *rdstypes.DBInstanceNotFoundFault
+		clusterNotFound *rdstypes.DBClusterNotFoundFault
+		api             smithy.APIError
+	)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &dbNotFound), errors.As(err, &clusterNotFound):
+		return cache.NotFound(err)
+	case errors.As(err, &api) && api.ErrorCode() == "InvalidInstanceType":
+		return cache.NotFound(err)
+	default:
+		return err
+	}
+}
+
+//
+//
+
+type cachedRDS struct {
+	RDS
+	instances   *cache.Cache[string, *rds.DescribeDBInstancesOutput]
+	clusters    *cache.Cache[string, *rds.DescribeDBClustersOutput]
+	maintenance *cache.Cache[string, *rds.DescribePendingMaintenanceActionsOutput]
+}
+
+func (c *cachedRDS) DescribeDBInstances(ctx context.Context, req *rds.DescribeDBInstancesInput, opts ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	return c.instances.Get(ctx, keyOf(req), func(ctx context.Context) (*rds.DescribeDBInstancesOutput, error) {
+		val, err := c.RDS.DescribeDBInstances(ctx, req, opts...)
+		return val, notFound(err)
+	})
+}
+
+func (c *cachedRDS) DescribeDBClusters(ctx context.Context, req *rds.DescribeDBClustersInput, opts ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return c.clusters.Get(ctx, keyOf(req), func(ctx context.Context) (*rds.DescribeDBClustersOutput, error) {
+		val, err := c.RDS.DescribeDBClusters(ctx, req, opts...)
+		return val, notFound(err)
+	})
+}
+
+func (c *cachedRDS) DescribePendingMaintenanceActions(ctx context.Context, req *rds.DescribePendingMaintenanceActionsInput, opts ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	return c.maintenance.Get(ctx, keyOf(req), func(ctx context.Context) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+		return c.RDS.DescribePendingMaintenanceActions(ctx, req, opts...)
+	})
+}
+
+//
+//
+
+type cachedEC2 struct {
+	instance.Provider
+	types *cache.Cache[string, *ec2.DescribeInstanceTypesOutput]
+}
+
+func (c *cachedEC2) DescribeInstanceTypes(ctx context.Context, req *ec2.DescribeInstanceTypesInput, opts ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	return c.types.Get(ctx, keyOf(req), func(ctx context.Context) (*ec2.DescribeInstanceTypesOutput, error) {
+		val, err := c.Provider.DescribeInstanceTypes(ctx, req, opts...)
+		return val, notFound(err)
+	})
+}
+
+//
+//
+
+type cachedPI struct {
+	insight.Provider
+	metrics *cache.Cache[string, *pi.GetResourceMetricsOutput]
+}
+
+// The window of metrics is defined by current time, the cache uses duration
+// of the window as key so that repeated requests within TTL hit the cache.
+func (c *cachedPI) GetResourceMetrics(ctx context.Context, req *pi.GetResourceMetricsInput, opts ...func(*pi.Options)) (*pi.GetResourceMetricsOutput, error) {
+	key := *req
+	key.StartTime, key.EndTime = nil, nil
+
+	window := aws.ToTime(req.EndTime).Sub(aws.ToTime(req.StartTime)).Round(time.Minute)
+
+	return c.metrics.Get(ctx, keyOf(struct {
+		Request *pi.GetResourceMetricsInput
+		Window  time.Duration
+	}{&key, window}), func(ctx context.Context) (*pi.GetResourceMetricsOutput, error) {
+		return c.Provider.GetResourceMetrics(ctx, req, opts...)
+	})
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/zalando/rds-health/internal/database"
+	"github.com/zalando/rds-health/internal/service"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// RDS that does not know any instance
+type emptyRDS struct{ calls int }
+
+func (r *emptyRDS) DescribeDBInstances(context.Context, *rds.DescribeDBInstancesInput, ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	r.calls++
+	return nil, &rdstypes.DBInstanceNotFoundFault{Message: aws.String("DBInstance a not found.")}
+}
+
+func (r *emptyRDS) DescribeDBClusters(context.Context, *rds.DescribeDBClustersInput, ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return &rds.DescribeDBClustersOutput{}, nil
+}
+
+func (r *emptyRDS) DescribePendingMaintenanceActions(context.Context, *rds.DescribePendingMaintenanceActionsInput, ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	return &rds.DescribePendingMaintenanceActionsOutput{}, nil
+}
+
+func TestCacheNotFound(t *testing.T) {
+	conf := aws.Config{
+		Region: "eu-central-1",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test"}, nil
+		}),
+	}
+
+	dir := t.TempDir()
+	api := &emptyRDS{}
+
+	lookup := func() error {
+		p, err := service.Providers{RDS: api}.WithCache(conf, dir, time.Hour)
+		if err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+
+		db := database.New(p.RDS)
+		if _, err := db.Lookup(context.TODO(), "a"); err != nil {
+			return err
+		}
+		_, err = db.Lookup(context.TODO(), "a")
+		return err
+	}
+
+	// fresh and cached in memory, then cached on disk by another process
+	for i := 0; i < 2; i++ {
+		if err := lookup(); !errors.Is(err, database.ErrNotFound) {
+			t.Errorf("should fail with not found error |%v|", err)
+		}
+	}
+
+	if api.calls != 1 {
+		t.Errorf("should cache negative result |%d|", api.calls)
+	}
+}
+
+// RDS that changes status of the instance on every call
+type changingRDS struct{ deniedRDS }
+
+func (r *changingRDS) DescribeDBInstances(ctx context.Context, in *rds.DescribeDBInstancesInput, opts ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	out, _ := r.deniedRDS.DescribeDBInstances(ctx, in, opts...)
+	out.DBInstances[0].DBInstanceStatus = aws.String(time.Now().Format(time.RFC3339Nano))
+	return out, nil
+}
+
+// refreshes of watch and serve run without cache, each of them observes the
+// current status
+func TestRefreshWithoutCache(t *testing.T) {
+	conf := aws.Config{
+		Region: "eu-central-1",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test"}, nil
+		}),
+	}
+
+	refresh := func(p service.Providers) []string {
+		api := service.New(p, &progress{})
+
+		seq := make([]string, 0)
+		for i := 0; i < 2; i++ {
+			region, err := api.ShowRegion(context.Background(), types.Filter{})
+			if err != nil {
+				t.Fatalf("should not fail with error %s", err)
+			}
+			seq = append(seq, region.Nodes[0].Config.Status)
+		}
+		return seq
+	}
+
+	if seq := refresh(service.Providers{RDS: &changingRDS{}}); seq[0] == seq[1] {
+		t.Errorf("should fetch fresh data on each refresh |%v|", seq)
+	}
+
+	cached, err := service.Providers{RDS: &changingRDS{}}.WithCache(conf, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	if seq := refresh(cached); seq[0] != seq[1] {
+		t.Errorf("should replay cached data within time-to-live |%v|", seq)
+	}
+}
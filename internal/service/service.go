@@ -10,16 +10,19 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/pi"
-	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/zalando/rds-health/internal/audit"
+	"github.com/zalando/rds-health/internal/catalog"
 	"github.com/zalando/rds-health/internal/database"
 	"github.com/zalando/rds-health/internal/discovery"
 	"github.com/zalando/rds-health/internal/insight"
 	"github.com/zalando/rds-health/internal/instance"
+	"github.com/zalando/rds-health/internal/rightsize"
 	"github.com/zalando/rds-health/internal/rules"
 	"github.com/zalando/rds-health/internal/types"
 )
@@ -36,26 +39,23 @@ type Service struct {
 	discovery *discovery.Discovery
 }
 
-func New(conf aws.Config, progress ProgressBar) *Service {
-	rds := rds.NewFromConfig(conf)
-	ec2 := ec2.NewFromConfig(conf)
-
+func New(providers Providers, progress ProgressBar) *Service {
 	return &Service{
 		progress:  progress,
-		database:  database.New(rds),
-		instance:  instance.New(ec2),
-		insight:   insight.New(pi.NewFromConfig(conf)),
-		discovery: discovery.New(rds, rds, ec2),
+		database:  database.New(providers.RDS),
+		instance:  instance.New(providers.EC2),
+		insight:   insight.New(providers.PI),
+		discovery: discovery.New(providers.RDS, providers.RDS, providers.EC2),
 	}
 }
 
 //
 //
 
-func (service *Service) CheckHealthRegion(ctx context.Context, interval time.Duration) (*types.StatusRegion, error) {
+func (service *Service) CheckHealthRegion(ctx context.Context, filter types.Filter, interval time.Duration) (*types.StatusRegion, error) {
 	service.progress.Describe("discovering")
 
-	clusters, nodes, err := service.discovery.LookupAll(context.Background())
+	clusters, nodes, err := service.discovery.LookupAll(context.Background(), filter)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +96,12 @@ func (service *Service) CheckHealthRegion(ctx context.Context, interval time.Dur
 			}
 		}
 
+		traffic, err := service.readerTraffic(ctx, cluster.Reader, interval)
+		if err != nil {
+			return nil, err
+		}
+
+		status = rules.Cluster(status, traffic)
 		region.Clusters[c] = status
 		if region.Status < status.Status {
 			region.Status = status.Status
@@ -116,6 +122,25 @@ func (service *Service) CheckHealthRegion(ctx context.Context, interval time.Dur
 	return &region, nil
 }
 
+// traffic of readers, the peak of rows fetched per second by node name
+func (service *Service) readerTraffic(ctx context.Context, readers []types.Node, interval time.Duration) (map[string]float64, error) {
+	traffic := make(map[string]float64, len(readers))
+	for _, node := range readers {
+		service.progress.Describe("checking traffic " + node.Name)
+
+		status, err := rules.New(service.insight).
+			Should(rules.SqlTuplesFetched.ShowMinMax()).
+			Run(ctx, node.ID, interval)
+		if err != nil {
+			return nil, err
+		}
+
+		traffic[node.Name] = status[0].HardMM.Max
+	}
+
+	return traffic, nil
+}
+
 func (service *Service) CheckHealthNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error) {
 	service.progress.Describe("discovering " + name)
 
@@ -168,14 +193,35 @@ func (service *Service) checkHealthNode(ctx context.Context, node types.Node, in
 //
 //
 
-func (service *Service) ShowRegion(ctx context.Context) (*types.Region, error) {
+func (service *Service) ShowRegion(ctx context.Context, filter types.Filter) (*types.Region, error) {
 	service.progress.Describe("discovering")
 
-	clusters, nodes, err := service.discovery.LookupAll(context.Background())
+	clusters, nodes, err := service.discovery.LookupAll(context.Background(), filter)
 	if err != nil {
 		return nil, err
 	}
 
+	service.progress.Describe("discovering maintenance")
+
+	// maintenance is optional, e.g. the permission to read it is not granted
+	actions, err := service.database.LookupMaintenance(ctx)
+	if err != nil {
+		service.progress.Describe("maintenance is not discovered: " + err.Error())
+	}
+
+	withMaintenance := func(nodes []types.Node) {
+		for i := range nodes {
+			nodes[i].Maintenance = actions[nodes[i].ARN]
+		}
+	}
+
+	for i := range clusters {
+		clusters[i].Maintenance = actions[clusters[i].ARN]
+		withMaintenance(clusters[i].Writer)
+		withMaintenance(clusters[i].Reader)
+	}
+	withMaintenance(nodes)
+
 	return &types.Region{
 		Clusters: clusters,
 		Nodes:    nodes,
@@ -185,40 +231,261 @@ func (service *Service) ShowRegion(ctx context.Context) (*types.Region, error) {
 //
 //
 
-func (service *Service) ShowNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error) {
-	service.progress.Describe("checking " + name)
+func (service *Service) AuditRegion(ctx context.Context, filter types.Filter, rules []audit.Rule) (*types.StatusRegion, error) {
+	region, err := service.ShowRegion(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	service.progress.Describe("auditing")
+
+	status := audit.New(rules...).Region(*region)
+	return &status, nil
+}
+
+func (service *Service) AuditNode(ctx context.Context, name string, rules []audit.Rule) (*types.StatusNode, error) {
+	service.progress.Describe("discovering " + name)
 
-	db, err := service.database.Lookup(context.Background(), name)
+	node, err := service.database.Lookup(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
-	db.Compute, _ = service.instance.Lookup(context.Background(), db.Type)
+	node.Compute, _ = service.instance.Lookup(context.Background(), node.Type)
+
+	// members of cluster are audited in the context of cluster
+	if node.Cluster != "" {
+		clusters, _, err := service.discovery.LookupAll(ctx, types.Filter{Clusters: []string{node.Cluster}})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range clusters {
+			status := audit.New(rules...).Cluster(cluster)
+			for _, member := range slices.Concat(status.Writer, status.Reader) {
+				if member.Node.Name == name {
+					return &member, nil
+				}
+			}
+		}
+	}
 
-	node := types.StatusNode{Node: db}
-	node.Checks, err = rules.New(service.insight).
-		Should(rules.DbXactCommit.ShowMinMax()).
-		Should(rules.SqlTuplesFetched.ShowMinMax()).
-		Should(rules.SqlTuplesReturned.ShowMinMax()).
-		Should(rules.SqlTuplesInserted.ShowMinMax()).
-		Should(rules.SqlTuplesUpdated.ShowMinMax()).
-		Should(rules.SqlTuplesDeleted.ShowMinMax()).
+	status := audit.New(rules...).Node(*node, nil)
+	return &status, nil
+}
+
+//
+//
+
+func (service *Service) RightsizeRegion(ctx context.Context, filter types.Filter, interval time.Duration) ([]types.Rightsize, error) {
+	service.progress.Describe("discovering")
+
+	clusters, nodes, err := service.discovery.LookupAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		nodes = append(nodes, slices.Concat(cluster.Writer, cluster.Reader)...)
+	}
+
+	seq := make([]types.Rightsize, len(nodes))
+	for i, node := range nodes {
+		v, err := service.rightsizeNode(ctx, node, interval)
+		if err != nil {
+			return nil, err
+		}
+		seq[i] = *v
+	}
+
+	return seq, nil
+}
+
+func (service *Service) RightsizeNode(ctx context.Context, name string, interval time.Duration) (*types.Rightsize, error) {
+	service.progress.Describe("discovering " + name)
+
+	node, err := service.database.Lookup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	node.Compute, _ = service.instance.Lookup(context.Background(), node.Type)
+
+	return service.rightsizeNode(ctx, *node, interval)
+}
+
+func (service *Service) rightsizeNode(ctx context.Context, node types.Node, interval time.Duration) (*types.Rightsize, error) {
+	service.progress.Describe("sizing " + node.Name)
+
+	status, err := rules.New(service.insight).
 		Should(rules.OsCpuUtil.ShowMinMax()).
-		Should(rules.OsCpuWait.ShowMinMax()).
-		Should(rules.DbStorageReadIO.ShowMinMax()).
-		Should(rules.DbStorageWriteIO.ShowMinMax()).
-		Should(rules.DbDataBlockReadIO.ShowMinMax()).
-		Should(rules.DbDataBlockCacheHit.ShowMinMax()).
-		Should(rules.DbBuffersCheckpoints.ShowMinMax()).
-		Should(rules.DbBuffersCheckpointsTime.ShowMinMax()).
+		Should(rules.DbLoad.ShowMinMax()).
+		Should(rules.OsMemoryTotal.ShowMinMax()).
 		Should(rules.OsMemoryFree.ShowMinMax()).
 		Should(rules.OsMemoryCached.ShowMinMax()).
-		Should(rules.OsFileSysUsed.ShowMinMax()).
-		Run(context.Background(), db.ID, interval)
+		Run(ctx, node.ID, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	// memory is reported in KB, the lowest free and cached memory is used
+	// to estimate memory used by processes at peak
+	const kb2gb = 1024.0 * 1024.0
+	total, free, cached := status[2].HardMM.Avg, status[3].HardMM.Min, status[4].HardMM.Min
+
+	usage := rightsize.Usage{
+		CPU:    status[0].SoftMM.Max,
+		Load:   status[1].SoftMM.Avg,
+		Memory: math.Max(total-free-cached, 0) / kb2gb,
+	}
+
+	sizing := rightsize.New(catalog.Default).Recommend(node, usage)
+	return &sizing, nil
+}
+
+//
+//
 
+func (service *Service) ShowNode(ctx context.Context, name string, interval time.Duration) (*types.StatusNode, error) {
+	service.progress.Describe("checking " + name)
+
+	db, err := service.database.Lookup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	db.Compute, _ = service.instance.Lookup(context.Background(), db.Type)
+
+	return service.showNode(ctx, *db, nil, interval)
+}
+
+func (service *Service) ShowUsageCluster(ctx context.Context, id string, interval time.Duration) (*types.StatusCluster, error) {
+	service.progress.Describe("discovering " + id)
+
+	clusters, _, err := service.discovery.LookupAll(ctx, types.Filter{Clusters: []string{id}})
+	if err != nil {
+		return nil, err
+	}
+
+	at := slices.IndexFunc(clusters, func(c types.Cluster) bool { return c.ID == id })
+	if at == -1 {
+		return nil, fmt.Errorf("%w: rds cluster %s", database.ErrNotFound, id)
+	}
+
+	status, err := service.showCluster(ctx, clusters[at], nil, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Checks = rules.Totals(slices.Concat(status.Writer, status.Reader))
+	return status, nil
+}
+
+// ShowUsageRegion shows utilization of given metrics, all metrics of
+// utilization are shown if none is given (see rules.UsageMetrics).
+func (service *Service) ShowUsageRegion(ctx context.Context, filter types.Filter, metrics []string, interval time.Duration) (*types.StatusRegion, error) {
+	service.progress.Describe("discovering")
+
+	clusters, nodes, err := service.discovery.LookupAll(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return &node, nil
+	region := types.StatusRegion{
+		Clusters: make([]types.StatusCluster, len(clusters)),
+		Nodes:    make([]types.StatusNode, len(nodes)),
+	}
+
+	for i, cluster := range clusters {
+		v, err := service.showCluster(ctx, cluster, metrics, interval)
+		if err != nil {
+			return nil, err
+		}
+		region.Clusters[i] = *v
+	}
+
+	for i, node := range nodes {
+		v, err := service.showNode(ctx, node, metrics, interval)
+		if err != nil {
+			return nil, err
+		}
+		region.Nodes[i] = *v
+	}
+
+	return &region, nil
+}
+
+func (service *Service) showCluster(ctx context.Context, cluster types.Cluster, metrics []string, interval time.Duration) (*types.StatusCluster, error) {
+	status := types.StatusCluster{
+		Cluster: &cluster,
+		Writer:  make([]types.StatusNode, len(cluster.Writer)),
+		Reader:  make([]types.StatusNode, len(cluster.Reader)),
+	}
+
+	for i, node := range cluster.Writer {
+		v, err := service.showNode(ctx, node, metrics, interval)
+		if err != nil {
+			return nil, err
+		}
+		status.Writer[i] = *v
+	}
+
+	for i, node := range cluster.Reader {
+		v, err := service.showNode(ctx, node, metrics, interval)
+		if err != nil {
+			return nil, err
+		}
+		status.Reader[i] = *v
+	}
+
+	return &status, nil
+}
+
+func (service *Service) showNode(ctx context.Context, node types.Node, metrics []string, interval time.Duration) (*types.StatusNode, error) {
+	service.progress.Describe("checking " + node.Name)
+
+	checks, err := rules.NewUsage(service.insight, metrics...).Run(ctx, node.ID, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.StatusNode{Node: &node, Checks: checks}, nil
+}
+
+//
+//
+
+func (service *Service) ExportNode(ctx context.Context, name string, interval time.Duration, metrics []string) ([]types.Point, error) {
+	service.progress.Describe("discovering " + name)
+
+	node, err := service.database.Lookup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	service.progress.Describe("exporting " + name)
+
+	series, err := service.insight.Fetch(ctx, node.ID, interval, metrics...)
+	if err != nil {
+		return nil, err
+	}
+
+	seq := make([]types.Point, 0)
+	for _, key := range metrics {
+		metric, agg := key, ""
+		if at := strings.LastIndexByte(key, '.'); at != -1 {
+			metric, agg = key[:at], key[at+1:]
+		}
+
+		for _, sample := range series[key] {
+			point := types.Point{Time: sample.T(), Metric: metric, Aggregator: agg}
+			if sample.Has() {
+				value := sample.X()
+				point.Value = &value
+			}
+			seq = append(seq, point)
+		}
+	}
+
+	return seq, nil
 }
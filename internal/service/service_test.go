@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package service_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+	"github.com/zalando/rds-health/internal/service"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+// RDS that denies access to pending maintenance actions
+type deniedRDS struct{}
+
+func (deniedRDS) DescribeDBInstances(context.Context, *rds.DescribeDBInstancesInput, ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	return &rds.DescribeDBInstancesOutput{
+		DBInstances: []rdstypes.DBInstance{
+			{
+				DBInstanceIdentifier: aws.String("a"),
+				DBInstanceArn:        aws.String("arn:aws:rds:eu-central-1:000000000000:db:a"),
+				DBInstanceClass:      aws.String("db.r5.large"),
+				Engine:               aws.String("postgres"),
+				EngineVersion:        aws.String("14.7"),
+			},
+		},
+	}, nil
+}
+
+func (deniedRDS) DescribeDBClusters(context.Context, *rds.DescribeDBClustersInput, ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	return &rds.DescribeDBClustersOutput{}, nil
+}
+
+func (deniedRDS) DescribePendingMaintenanceActions(context.Context, *rds.DescribePendingMaintenanceActionsInput, ...func(*rds.Options)) (*rds.DescribePendingMaintenanceActionsOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized to perform: rds:DescribePendingMaintenanceActions"}
+}
+
+// progress bar that records descriptions
+type progress []string
+
+func (p *progress) Describe(s string) { *p = append(*p, s) }
+
+func TestShowRegionWithoutMaintenance(t *testing.T) {
+	bar := &progress{}
+	api := service.New(service.Providers{RDS: deniedRDS{}}, bar)
+
+	region, err := api.ShowRegion(context.Background(), types.Filter{})
+	switch {
+	case err != nil:
+		t.Fatalf("should not fail with error %s", err)
+	case len(region.Nodes) != 1 || region.Nodes[0].Maintenance != nil:
+		t.Errorf("unexpected region |%+v|", region)
+	}
+
+	if !slices.ContainsFunc(*bar, func(s string) bool { return strings.Contains(s, "AccessDenied") }) {
+		t.Errorf("should warn about maintenance |%v|", *bar)
+	}
+}
@@ -0,0 +1,236 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package chart
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package draws time series of rules in terminal: unicode sparklines
+// to reveal the shape of data in one line and ASCII line charts with
+// threshold markers and time axis labels.
+//
+
+var ticks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline of the sequence using width characters at most, missing values
+// are blank.
+func Sparkline(seq []float64, width int) string {
+	if len(seq) == 0 || width <= 0 {
+		return ""
+	}
+
+	width = min(width, len(seq))
+	cols := make([]float64, width)
+	lo, hi := math.Inf(+1), math.Inf(-1)
+	for c := 0; c < width; c++ {
+		from, to := bucket(c, width, len(seq))
+		cols[c] = mean(seq[from:to])
+		if !math.IsNaN(cols[c]) {
+			lo, hi = math.Min(lo, cols[c]), math.Max(hi, cols[c])
+		}
+	}
+
+	sb := strings.Builder{}
+	for _, x := range cols {
+		switch {
+		case math.IsNaN(x):
+			sb.WriteRune(' ')
+		case hi == lo:
+			sb.WriteRune(ticks[0])
+		default:
+			sb.WriteRune(ticks[int(math.Round((x-lo)/(hi-lo)*float64(len(ticks)-1)))])
+		}
+	}
+
+	return sb.String()
+}
+
+// Plot the series of the rule as ASCII line chart of given size. The avg
+// is drawn with '*', the min-max range with '.' and thresholds with '-'.
+func Plot(s types.Status, width, height int) string {
+	series := s.Series
+	if series == nil || len(series.Time) == 0 {
+		return ""
+	}
+
+	const (
+		labelW  = 10 // "%9.3g " left axis labels
+		markerW = 16 // " < avg 1000" right threshold markers
+	)
+
+	height = max(height, 5)
+	plotW := max(width-labelW-1-markerW, 10)
+
+	// aggregate samples to columns
+	n := len(series.Time)
+	mins, avgs, maxs := make([]float64, plotW), make([]float64, plotW), make([]float64, plotW)
+	lo, hi := math.Inf(+1), math.Inf(-1)
+	for c := 0; c < plotW; c++ {
+		from, to := bucket(c, plotW, n)
+		mins[c] = minOf(series.Min[from:to])
+		avgs[c] = mean(series.Avg[from:to])
+		maxs[c] = maxOf(series.Max[from:to])
+		for _, x := range []float64{mins[c], avgs[c], maxs[c]} {
+			if !math.IsNaN(x) {
+				lo, hi = math.Min(lo, x), math.Max(hi, x)
+			}
+		}
+	}
+
+	if math.IsInf(lo, 0) {
+		return ""
+	}
+
+	if s.Threshold != nil {
+		lo = math.Min(lo, math.Min(s.Threshold.Avg, s.Threshold.Peak))
+		hi = math.Max(hi, math.Max(s.Threshold.Avg, s.Threshold.Peak))
+	}
+
+	if lo >= 0 {
+		lo = 0
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+
+	row := func(y float64) int {
+		return int(math.Round((hi - y) / (hi - lo) * float64(height-1)))
+	}
+
+	grid := make([][]rune, height)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", plotW))
+	}
+
+	markers := make([]string, height)
+	if s.Threshold != nil {
+		peak := "max"
+		if s.Threshold.Op == "above" {
+			peak = "min"
+		}
+
+		for _, t := range []struct {
+			label string
+			value float64
+		}{{"avg", s.Threshold.Avg}, {peak, s.Threshold.Peak}} {
+			r := row(t.value)
+			for c := range grid[r] {
+				grid[r][c] = '-'
+			}
+			markers[r] += fmt.Sprintf(" < %s %g", t.label, t.value)
+		}
+	}
+
+	for c := 0; c < plotW; c++ {
+		if !math.IsNaN(mins[c]) && !math.IsNaN(maxs[c]) {
+			for r := row(maxs[c]); r <= row(mins[c]); r++ {
+				grid[r][c] = '.'
+			}
+		}
+		if !math.IsNaN(avgs[c]) {
+			grid[row(avgs[c])][c] = '*'
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s (%s) on %s\n", strings.TrimSpace(s.Rule.String()), s.Rule.Unit, s.Interval))
+
+	for r := 0; r < height; r++ {
+		label := ""
+		if r == 0 || r == height-1 || r == height/2 {
+			y := hi - (hi-lo)*float64(r)/float64(height-1)
+			if math.Abs(y) < (hi-lo)*1e-9 {
+				y = 0
+			}
+			label = fmt.Sprintf("%.3g", y)
+		}
+		sb.WriteString(fmt.Sprintf("%*s |%s%s\n", labelW-1, label, string(grid[r]), markers[r]))
+	}
+
+	sb.WriteString(fmt.Sprintf("%*s +%s\n", labelW-1, "", strings.Repeat("-", plotW)))
+	sb.WriteString(fmt.Sprintf("%*s  %s\n", labelW-1, "", axis(series.Time[0], series.Time[n-1], plotW)))
+
+	return sb.String()
+}
+
+// time axis labels at the beginning, the middle and the end of axis
+func axis(t0, t1 time.Time, width int) string {
+	layout := "01-02 15:04"
+	if t1.Sub(t0) < 24*time.Hour {
+		layout = "15:04"
+	}
+
+	line := []rune(strings.Repeat(" ", width))
+	put := func(at int, text string) {
+		for i, r := range []rune(text) {
+			if at+i >= 0 && at+i < width {
+				line[at+i] = r
+			}
+		}
+	}
+
+	a, b := t0.UTC().Format(layout), t1.UTC().Format(layout)
+	put(0, a)
+	put(width-len(b), b)
+
+	if width >= 4*len(a) {
+		m := t0.Add(t1.Sub(t0) / 2).UTC().Format(layout)
+		put(width/2-len(m)/2, m)
+	}
+
+	return strings.TrimRight(string(line), " ")
+}
+
+// indexes [from, to) of samples aggregated to column c, non empty
+func bucket(c, cols, n int) (int, int) {
+	from := c * n / cols
+	to := max((c+1)*n/cols, from+1)
+	return from, min(to, n)
+}
+
+func mean(seq []float64) float64 {
+	sum, cnt := 0.0, 0
+	for _, x := range seq {
+		if !math.IsNaN(x) {
+			sum += x
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		return math.NaN()
+	}
+	return sum / float64(cnt)
+}
+
+func minOf(seq []float64) float64 {
+	v := math.NaN()
+	for _, x := range seq {
+		if !math.IsNaN(x) && (math.IsNaN(v) || x < v) {
+			v = x
+		}
+	}
+	return v
+}
+
+func maxOf(seq []float64) float64 {
+	v := math.NaN()
+	for _, x := range seq {
+		if !math.IsNaN(x) && (math.IsNaN(v) || x > v) {
+			v = x
+		}
+	}
+	return v
+}
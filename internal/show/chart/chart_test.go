@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package chart_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/show/chart"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestSparkline(t *testing.T) {
+	nan := math.NaN()
+
+	for name, tt := range map[string]struct {
+		seq    []float64
+		width  int
+		expect string
+	}{
+		"empty":          {seq: nil, width: 8, expect: ""},
+		"zero width":     {seq: []float64{1, 2}, width: 0, expect: ""},
+		"scale":          {seq: []float64{0, 1, 2, 3, 4, 5, 6, 7}, width: 8, expect: "▁▂▃▄▅▆▇█"},
+		"constant":       {seq: []float64{5, 5, 5}, width: 8, expect: "▁▁▁"},
+		"aggregated":     {seq: []float64{0, 0, 7, 7}, width: 2, expect: "▁█"},
+		"missing values": {seq: []float64{0, nan, 7}, width: 8, expect: "▁ █"},
+		"missing bucket": {seq: []float64{0, 0, nan, nan, 7, 7}, width: 3, expect: "▁ █"},
+		"no values":      {seq: []float64{nan, nan}, width: 8, expect: "  "},
+		"skip nan":       {seq: []float64{0, nan, 7, 7}, width: 2, expect: "▁█"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if v := chart.Sparkline(tt.seq, tt.width); v != tt.expect {
+				t.Errorf("unexpected sparkline |%s| != |%s|", v, tt.expect)
+			}
+		})
+	}
+}
+
+func TestPlot(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := func(seq ...float64) *types.Series {
+		s := &types.Series{}
+		for i, x := range seq {
+			s.Time = append(s.Time, t0.Add(time.Duration(i)*time.Hour))
+			s.Min = append(s.Min, x)
+			s.Avg = append(s.Avg, x)
+			s.Max = append(s.Max, x)
+		}
+		return s
+	}
+
+	for name, tt := range map[string]struct {
+		status types.Status
+		has    []string
+		hasNot []string
+	}{
+		"no series": {
+			status: types.Status{},
+		},
+		"no values": {
+			status: types.Status{Series: series(math.NaN(), math.NaN())},
+		},
+		"line": {
+			status: types.Status{Rule: types.Rule{About: "cpu utilization", Unit: "%"}, Series: series(10, 20, 30, 40)},
+			has:    []string{"cpu utilization (%)", "*", "00:00", "03:00"},
+			hasNot: []string{"<", "NaN"},
+		},
+		"thresholds below": {
+			status: types.Status{Series: series(10, 20), Threshold: &types.Threshold{Op: "below", Avg: 40, Peak: 60}},
+			has:    []string{"< avg 40", "< max 60", "---"},
+		},
+		"thresholds above": {
+			status: types.Status{Series: series(90, 95), Threshold: &types.Threshold{Op: "above", Avg: 80, Peak: 50}},
+			has:    []string{"< avg 80", "< min 50"},
+		},
+		"missing values": {
+			status: types.Status{Series: series(10, math.NaN(), 30)},
+			has:    []string{"*"},
+			hasNot: []string{"NaN"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v := chart.Plot(tt.status, 60, 8)
+			if len(tt.has) == 0 && v != "" {
+				t.Errorf("should not plot |%s|", v)
+			}
+
+			for _, s := range tt.has {
+				if !strings.Contains(v, s) {
+					t.Errorf("should contain %s |\n%s|", s, v)
+				}
+			}
+			for _, s := range tt.hasNot {
+				if strings.Contains(v, s) {
+					t.Errorf("should not contain %s |\n%s|", s, v)
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ package compact
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/zalando/rds-health/internal/show"
 	"github.com/zalando/rds-health/internal/types"
@@ -64,3 +65,80 @@ var (
 		},
 	}
 )
+
+var (
+	// FAIL example-cluster ¦ R4: members in single zone (eu-central-1a)
+	//
+	showHealthCluster = show.FromShow[types.StatusCluster](
+		func(c types.StatusCluster) ([]byte, error) {
+			status := show.StatusText(c.Status)
+			text := fmt.Sprintf("%s "+show.SCHEMA.Cluster+"%s\n\n", status, c.Cluster.ID, clusterRules(c.Checks))
+			return []byte(text), nil
+		},
+	)
+
+	// Show cluster health status and its nodes, rules that pass are omitted
+	ShowHealthCluster = show.Cluster(
+		showHealthCluster,
+		ShowHealthNode,
+		func(sc types.StatusCluster) ([]types.StatusNode, []types.StatusNode) { return sc.Writer, sc.Reader },
+	)
+
+	// Show health of clusters and nodes in the region
+	ShowHealthRegion = show.Region[types.StatusRegion](
+		ShowHealthCluster,
+		ShowHealthNode,
+		func(sr types.StatusRegion) ([]types.StatusCluster, []types.StatusNode) { return sr.Clusters, sr.Nodes },
+	)
+)
+
+var (
+	//	cpu utilization (%)
+	//		min: 17.50	avg: 25.00	max: 80.00
+	showValueRule = show.FromShow[types.Status](
+		func(status types.Status) ([]byte, error) {
+			if status.SoftMM == nil {
+				return nil, nil
+			}
+
+			soft, _ := showMinMax.Show(*status.SoftMM)
+
+			b := &bytes.Buffer{}
+			b.WriteString(fmt.Sprintf("%s (%s)\n", status.Rule.About, status.Rule.Unit))
+			b.WriteString(fmt.Sprintf("\t%s\n", string(soft)))
+			return b.Bytes(), nil
+		},
+	)
+
+	showValueNode = show.FromShow[types.StatusNode](
+		func(node types.StatusNode) ([]byte, error) {
+			text := fmt.Sprintf("%s (%s, %s)\n\n", node.Node.Name, node.Node.Engine, node.Node.Type)
+			return []byte(text), nil
+		},
+	)
+
+	// Show soft min, avg and max of metrics observed by the node
+	ShowValueNode = show.Printer2[types.StatusNode, types.StatusNode, []types.Status]{
+		A: showValueNode,
+		B: show.Seq[types.Status]{T: showValueRule},
+		UnApply2: func(sn types.StatusNode) (types.StatusNode, []types.Status) {
+			return sn, sn.Checks
+		},
+	}
+)
+
+// cluster rules that do not pass with observed values
+func clusterRules(checks []types.Status) string {
+	seq := make([]string, 0)
+	for _, status := range checks {
+		if status.Code > types.STATUS_CODE_SUCCESS {
+			seq = append(seq, fmt.Sprintf("%s: %s (%s)", status.Rule.ID, status.Rule.About, *status.Observed))
+		}
+	}
+
+	if len(seq) == 0 {
+		return ""
+	}
+
+	return " ¦ " + strings.Join(seq, ", ")
+}
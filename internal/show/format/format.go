@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+
+	"github.com/zalando/rds-health/internal/rules"
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// Show values using user-defined Go template, similar to docker inspect
+// --format. The helper functions of templates are
+//
+//	status   text of status code (PASS, WARN, FAIL, NONE), colored if enabled
+//	icon     emoji of status code
+//	unit     value with unit (e.g. {{unit .SoftMM.Avg .Rule.Unit}})
+//	rules    statuses of rules matching the profile or rule ids
+//	         (e.g. {{range rules "storage" .Checks}})
+//	failing  statuses of rules that do not pass
+//	nodes    instances of the region, including members of clusters
+//	join     strings.Join
+//	upper    strings.ToUpper
+//	lower    strings.ToLower
+//	json     value as JSON
+//
+
+// Template of the output
+func Template(text string) (*template.Template, error) {
+	return template.New("format").Funcs(template.FuncMap{
+		"status":  show.StatusText,
+		"icon":    statusIcon,
+		"unit":    unit,
+		"rules":   filterRules,
+		"failing": failing,
+		"nodes":   nodes,
+		"join":    strings.Join,
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"json":    toJSON,
+	}).Parse(text)
+}
+
+// Show value using the template, each value ends with new line
+func Show[T any](t *template.Template) show.Printer[T] {
+	return show.FromShow[T](
+		func(x T) ([]byte, error) {
+			b := &bytes.Buffer{}
+			if err := t.Execute(b, x); err != nil {
+				return nil, err
+			}
+
+			if b.Len() != 0 && !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+				b.WriteByte('\n')
+			}
+
+			return b.Bytes(), nil
+		},
+	)
+}
+
+func statusIcon(code types.StatusCode) string {
+	switch code {
+	case types.STATUS_CODE_SUCCESS:
+		return show.SCHEMA_COLOR.StatusCodeIcon.PASS
+	case types.STATUS_CODE_WARNING:
+		return show.SCHEMA_COLOR.StatusCodeIcon.WARN
+	case types.STATUS_CODE_FAILURE:
+		return show.SCHEMA_COLOR.StatusCodeIcon.FAIL
+	default:
+		return show.SCHEMA_COLOR.StatusCodeIcon.NONE
+	}
+}
+
+func unit(x float64, unit string) string {
+	if math.IsNaN(x) {
+		return "-"
+	}
+
+	if unit == "" {
+		return fmt.Sprintf("%.2f", x)
+	}
+
+	return fmt.Sprintf("%.2f %s", x, unit)
+}
+
+func filterRules(spec string, seq []types.Status) ([]types.Status, error) {
+	match, err := rules.Profile(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]types.Status, 0)
+	for _, s := range seq {
+		if match(s.Rule) {
+			out = append(out, s)
+		}
+	}
+
+	return out, nil
+}
+
+func failing(seq []types.Status) []types.Status {
+	out := make([]types.Status, 0)
+	for _, s := range seq {
+		if s.Code > types.STATUS_CODE_SUCCESS {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
+
+func nodes(v any) (any, error) {
+	switch r := v.(type) {
+	case types.Region:
+		seq := make([]types.Node, 0)
+		for _, c := range r.Clusters {
+			seq = append(append(seq, c.Writer...), c.Reader...)
+		}
+		return append(seq, r.Nodes...), nil
+	case types.StatusRegion:
+		seq := make([]types.StatusNode, 0)
+		for _, c := range r.Clusters {
+			seq = append(append(seq, c.Writer...), c.Reader...)
+		}
+		return append(seq, r.Nodes...), nil
+	case types.StatusNode:
+		return []types.StatusNode{r}, nil
+	default:
+		return nil, fmt.Errorf("nodes of %T are not supported", v)
+	}
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
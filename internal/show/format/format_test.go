@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package format_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zalando/rds-health/internal/show/format"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestShow(t *testing.T) {
+	node := func(name string, checks ...types.Status) types.StatusNode {
+		return types.StatusNode{Node: &types.Node{Name: name}, Checks: checks}
+	}
+
+	c1 := types.Status{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1", Unit: "%"}, SoftMM: &types.MinMax{Avg: 12.345}}
+	d3 := types.Status{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "D3", Unit: "ms"}, SoftMM: &types.MinMax{Avg: math.NaN()}}
+	p1 := types.Status{Code: types.STATUS_CODE_WARNING, Rule: types.Rule{ID: "P1"}, SoftMM: &types.MinMax{Avg: 0.5}}
+
+	region := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{Cluster: &types.Cluster{ID: "c"}, Writer: []types.StatusNode{node("w", c1)}, Reader: []types.StatusNode{node("r", d3)}},
+		},
+		Nodes: []types.StatusNode{node("a", c1, d3, p1)},
+	}
+
+	for name, tt := range map[string]struct {
+		template string
+		value    any
+		expect   string
+		fails    bool
+	}{
+		"new line":        {template: `{{len .Nodes}}`, value: region, expect: "1\n"},
+		"empty":           {template: `{{if false}}x{{end}}`, value: region, expect: ""},
+		"nodes of region": {template: `{{range nodes .}}{{.Node.Name}} {{end}}`, value: region, expect: "w r a \n"},
+		"nodes of node":   {template: `{{range nodes .}}{{.Node.Name}}{{end}}`, value: node("a"), expect: "a\n"},
+		"nodes of config": {template: `{{range nodes .}}{{.Name}} {{end}}`, value: types.Region{Clusters: []types.Cluster{{Writer: []types.Node{{Name: "w"}}}}, Nodes: []types.Node{{Name: "a"}}}, expect: "w a \n"},
+		"nodes of other":  {template: `{{nodes .}}`, value: "x", fails: true},
+		"rules by ids":    {template: `{{range rules "D3,P1" .Checks}}{{.Rule.ID}} {{end}}`, value: node("a", c1, d3, p1), expect: "D3 P1 \n"},
+		"rules all":       {template: `{{len (rules "all" .Checks)}}`, value: node("a", c1, d3, p1), expect: "3\n"},
+		"rules unknown":   {template: `{{rules "X9" .Checks}}`, value: node("a", c1), fails: true},
+		"failing":         {template: `{{range failing .Checks}}{{.Rule.ID}} {{end}}`, value: node("a", c1, d3, p1), expect: "D3 P1 \n"},
+		"failing none":    {template: `{{len (failing .Checks)}}`, value: node("a", c1), expect: "0\n"},
+		"unit":            {template: `{{range .Checks}}{{unit .SoftMM.Avg .Rule.Unit}};{{end}}`, value: node("a", c1, d3, p1), expect: "12.35 %;-;0.50;\n"},
+		"strings":         {template: `{{upper .Node.Name}}{{lower "B"}}`, value: node("a"), expect: "Ab\n"},
+		"undefined":       {template: `{{undefined .}}`, value: node("a"), fails: true},
+		"json":            {template: `{{json .Node.Name}}`, value: node("a"), expect: "\"a\"\n"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := format.Template(tt.template)
+			if err != nil {
+				if !tt.fails {
+					t.Errorf("should not fail with error %s", err)
+				}
+				return
+			}
+
+			b, err := format.Show[any](tmpl).Show(tt.value)
+			switch {
+			case tt.fails && err == nil:
+				t.Errorf("should fail |%s|", b)
+			case !tt.fails && err != nil:
+				t.Errorf("should not fail with error %s", err)
+			case !tt.fails && string(b) != tt.expect:
+				t.Errorf("unexpected output |%q| != |%q|", b, tt.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// Show health status as self-contained HTML report: the fleet summary,
+// the table of rules for each node and the inline SVG chart of each rule
+// drawn from raw samples with threshold lines. The report has no external
+// assets so that it can be shared as a single file.
+//
+
+type report struct {
+	Title    string
+	Summary  map[types.StatusCode]int
+	Nodes    []node
+	Clusters []node // cluster rules
+}
+
+type node struct {
+	Anchor  string
+	Name    string
+	Cluster string
+	Role    string
+	Engine  string
+	Class   string
+	Spec    string
+	Status  types.StatusCode
+	Failing []string
+	Rules   []rule
+}
+
+type rule struct {
+	types.Status
+	Chart template.HTML
+}
+
+func nodeOf(cluster, role string, n types.StatusNode) node {
+	v := node{
+		Anchor:  "node-" + n.Node.Name,
+		Name:    n.Node.Name,
+		Cluster: cluster,
+		Role:    role,
+		Class:   n.Node.Type,
+		Spec:    n.Node.String(),
+		Status:  n.Status,
+		Rules:   make([]rule, len(n.Checks)),
+	}
+
+	if n.Node.Engine != nil {
+		v.Engine = n.Node.Engine.String()
+	}
+
+	for i, s := range n.Checks {
+		v.Rules[i] = rule{Status: s, Chart: chart(s)}
+		if s.Code > types.STATUS_CODE_SUCCESS {
+			v.Failing = append(v.Failing, s.Rule.ID)
+		}
+	}
+
+	return v
+}
+
+// cluster rules as the node named after the cluster, its status is defined
+// by cluster rules only, members are shown by their own nodes
+func clusterOf(c types.StatusCluster) node {
+	status := types.StatusNode{
+		Status: types.STATUS_CODE_UNKNOWN,
+		Node:   &types.Node{Name: c.Cluster.ID, Engine: c.Cluster.Engine},
+		Checks: c.Checks,
+	}
+	for _, s := range c.Checks {
+		status.Status = max(status.Status, s.Code)
+	}
+
+	v := nodeOf(c.Cluster.ID, "cluster", status)
+	v.Anchor = "cluster-" + c.Cluster.ID
+	return v
+}
+
+func reportOf(title string, nodes ...node) report {
+	r := report{Title: title, Summary: map[types.StatusCode]int{}, Nodes: nodes}
+	for _, n := range nodes {
+		r.Summary[n.Status]++
+	}
+	return r
+}
+
+func roleOf(n types.StatusNode) string {
+	if n.Node.Cluster == "" {
+		return "instance"
+	}
+	if n.Node.ReadOnly {
+		return "reader"
+	}
+	return "writer"
+}
+
+//
+// Charts
+//
+
+const (
+	chartW = 640.0
+	chartH = 180.0
+	padL   = 56.0
+	padR   = 12.0
+	padT   = 12.0
+	padB   = 24.0
+)
+
+// inline SVG chart of rule series: min-max band, avg line and thresholds
+func chart(s types.Status) template.HTML {
+	series := s.Series
+	if series == nil || len(series.Time) < 2 {
+		return ""
+	}
+
+	lo, hi := math.Inf(+1), math.Inf(-1)
+	for _, seq := range [][]float64{series.Min, series.Avg, series.Max} {
+		for _, x := range seq {
+			if !math.IsNaN(x) && !math.IsInf(x, 0) {
+				lo, hi = math.Min(lo, x), math.Max(hi, x)
+			}
+		}
+	}
+
+	if math.IsInf(lo, 0) {
+		return ""
+	}
+
+	if s.Threshold != nil {
+		lo = math.Min(lo, math.Min(s.Threshold.Avg, s.Threshold.Peak))
+		hi = math.Max(hi, math.Max(s.Threshold.Avg, s.Threshold.Peak))
+	}
+
+	if lo >= 0 {
+		lo = 0
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	hi += (hi - lo) * 0.05
+
+	t0, t1 := series.Time[0], series.Time[len(series.Time)-1]
+	span := t1.Sub(t0).Seconds()
+	if span <= 0 {
+		span = 1
+	}
+
+	px := func(t time.Time) float64 { return padL + (chartW-padL-padR)*t.Sub(t0).Seconds()/span }
+	py := func(y float64) float64 { return padT + (chartH-padT-padB)*(hi-y)/(hi-lo) }
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %g %g" class="chart" role="img">`, chartW, chartH)
+	fmt.Fprintf(sb, `<title>%s</title>`, template.HTMLEscapeString(s.Rule.About))
+
+	// grid with labels
+	for i := 0; i <= 4; i++ {
+		y := lo + (hi-lo)*float64(i)/4
+		fmt.Fprintf(sb, `<line class="grid" x1="%g" y1="%.1f" x2="%g" y2="%.1f"/>`, padL, py(y), chartW-padR, py(y))
+		fmt.Fprintf(sb, `<text class="label" x="%g" y="%.1f" text-anchor="end">%s</text>`, padL-4, py(y)+4, template.HTMLEscapeString(fmt.Sprintf("%.3g", y)))
+	}
+	fmt.Fprintf(sb, `<text class="label" x="%g" y="%g">%s</text>`, padL, chartH-6, t0.UTC().Format("2006-01-02 15:04"))
+	fmt.Fprintf(sb, `<text class="label" x="%g" y="%g" text-anchor="end">%s</text>`, chartW-padR, chartH-6, t1.UTC().Format("2006-01-02 15:04"))
+
+	// min-max band and avg line are split at missing samples
+	for _, seg := range segments(series.Min, series.Max) {
+		pts := make([]string, 0, 2*(seg[1]-seg[0]))
+		for i := seg[0]; i < seg[1]; i++ {
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", px(series.Time[i]), py(series.Max[i])))
+		}
+		for i := seg[1] - 1; i >= seg[0]; i-- {
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", px(series.Time[i]), py(series.Min[i])))
+		}
+		fmt.Fprintf(sb, `<polygon class="band" points="%s"/>`, strings.Join(pts, " "))
+	}
+
+	for _, seg := range segments(series.Avg) {
+		pts := make([]string, 0, seg[1]-seg[0])
+		for i := seg[0]; i < seg[1]; i++ {
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", px(series.Time[i]), py(series.Avg[i])))
+		}
+		fmt.Fprintf(sb, `<polyline class="avg" points="%s"/>`, strings.Join(pts, " "))
+	}
+
+	if s.Threshold != nil {
+		peak := "max"
+		if s.Threshold.Op == "above" {
+			peak = "min"
+		}
+
+		fmt.Fprintf(sb, `<line class="warn" x1="%g" y1="%.1f" x2="%g" y2="%.1f"><title>avg threshold %g</title></line>`, padL, py(s.Threshold.Avg), chartW-padR, py(s.Threshold.Avg), s.Threshold.Avg)
+		fmt.Fprintf(sb, `<line class="fail" x1="%g" y1="%.1f" x2="%g" y2="%.1f"><title>%s threshold %g</title></line>`, padL, py(s.Threshold.Peak), chartW-padR, py(s.Threshold.Peak), peak, s.Threshold.Peak)
+	}
+
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
+
+// ranges [from, to) of indexes where all sequences have values
+func segments(seqs ...[]float64) [][2]int {
+	has := func(i int) bool {
+		for _, seq := range seqs {
+			if math.IsNaN(seq[i]) || math.IsInf(seq[i], 0) {
+				return false
+			}
+		}
+		return true
+	}
+
+	seg := make([][2]int, 0)
+	from := -1
+	for i := 0; i < len(seqs[0]); i++ {
+		switch {
+		case has(i) && from == -1:
+			from = i
+		case !has(i) && from != -1:
+			seg = append(seg, [2]int{from, i})
+			from = -1
+		}
+	}
+
+	if from != -1 {
+		seg = append(seg, [2]int{from, len(seqs[0])})
+	}
+
+	return seg
+}
+
+//
+// Template
+//
+
+func statusText(code types.StatusCode) string {
+	switch code {
+	case types.STATUS_CODE_SUCCESS:
+		return "PASS"
+	case types.STATUS_CODE_WARNING:
+		return "WARN"
+	case types.STATUS_CODE_FAILURE:
+		return "FAIL"
+	default:
+		return "NONE"
+	}
+}
+
+func statusClass(code types.StatusCode) string {
+	return strings.ToLower(statusText(code))
+}
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"status": statusText,
+	"class":  statusClass,
+	"codes": func() []types.StatusCode {
+		return []types.StatusCode{types.STATUS_CODE_FAILURE, types.STATUS_CODE_WARNING, types.STATUS_CODE_SUCCESS, types.STATUS_CODE_UNKNOWN}
+	},
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>rds-health: {{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.3em; margin-top: 2em; border-bottom: 1px solid #d0d7de; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #eaeef2; text-align: left; vertical-align: top; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.badge { display: inline-block; padding: 1px 8px; border-radius: 10px; font-size: 0.85em; font-weight: 600; color: #fff; }
+.badge.pass { background: #1a7f37; } .badge.warn { background: #bf8700; } .badge.fail { background: #cf222e; } .badge.none { background: #8c959f; }
+.summary span { margin-right: 1.5em; }
+.meta { color: #57606a; }
+.charts { display: flex; flex-wrap: wrap; gap: 1em; }
+figure { margin: 0; width: 640px; max-width: 100%; }
+figcaption { font-size: 0.9em; margin-bottom: 4px; }
+svg.chart { width: 100%; height: auto; background: #f6f8fa; }
+svg .grid { stroke: #d0d7de; stroke-width: 0.5; }
+svg .label { font-size: 10px; fill: #57606a; }
+svg .band { fill: #0969da; fill-opacity: 0.15; stroke: none; }
+svg .avg { fill: none; stroke: #0969da; stroke-width: 1.2; }
+svg .warn { stroke: #bf8700; stroke-width: 1; stroke-dasharray: 6 3; }
+svg .fail { stroke: #cf222e; stroke-width: 1; stroke-dasharray: 6 3; }
+</style>
+</head>
+<body>
+<h1>rds-health: {{.Title}}</h1>
+
+<p class="summary">{{$summary := .Summary}}<span>{{len .Nodes}} instance(s)</span>{{range $code := codes}}{{with index $summary $code}}<span><span class="badge {{class $code}}">{{status $code}}</span> {{.}}</span>{{end}}{{end}}</p>
+
+<table>
+<tr><th>status</th><th>instance</th><th>cluster</th><th>role</th><th>engine</th><th>class</th><th>rules</th></tr>
+{{range .Nodes}}<tr><td><span class="badge {{class .Status}}">{{status .Status}}</span></td><td><a href="#{{.Anchor}}">{{.Name}}</a></td><td>{{.Cluster}}</td><td>{{.Role}}</td><td>{{.Engine}}</td><td>{{.Class}}</td><td>{{join .Failing " "}}</td></tr>
+{{end}}</table>
+
+{{range .Clusters}}
+<h2 id="{{.Anchor}}"><span class="badge {{class .Status}}">{{status .Status}}</span> {{.Name}}</h2>
+<p class="meta">cluster rules{{with .Engine}}, {{.}}{{end}}</p>
+
+{{template "rules" .Rules}}
+{{end}}
+{{range .Nodes}}
+<h2 id="{{.Anchor}}"><span class="badge {{class .Status}}">{{status .Status}}</span> {{.Name}}</h2>
+<p class="meta">{{if .Cluster}}{{.Cluster}} ({{.Role}}), {{end}}{{.Spec}}</p>
+
+{{template "rules" .Rules}}
+
+<div class="charts">
+{{range .Rules}}{{if .Chart}}<figure><figcaption>{{with .Rule.ID}}{{.}}: {{end}}{{.Rule.About}}{{with .Rule.Unit}} ({{.}}){{end}}</figcaption>{{.Chart}}</figure>
+{{end}}{{end}}</div>
+{{end}}
+</body>
+</html>
+{{define "rules"}}<table>
+<tr><th>status</th><th>rule</th><th>%</th><th>min</th><th>avg</th><th>max</th><th>unit</th><th>threshold</th></tr>
+{{range .}}<tr><td><span class="badge {{class .Code}}">{{status .Code}}</span></td><td>{{with .Rule.ID}}{{.}}: {{end}}{{.Rule.About}}</td><td class="num">{{with .SuccessRate}}{{printf "%.2f" .}}{{end}}</td>{{with .SoftMM}}<td class="num">{{printf "%.2f" .Min}}</td><td class="num">{{printf "%.2f" .Avg}}</td><td class="num">{{printf "%.2f" .Max}}</td>{{else}}<td></td><td></td><td>{{with .Observed}}{{.}}{{end}}</td>{{end}}<td>{{.Rule.Unit}}</td><td>{{with .Threshold}}{{.}}{{end}}</td></tr>
+{{end}}</table>{{end}}`))
+
+var (
+	showReport = show.FromShow[report](
+		func(r report) ([]byte, error) {
+			b := &bytes.Buffer{}
+			if err := page.Execute(b, r); err != nil {
+				return nil, err
+			}
+			return b.Bytes(), nil
+		},
+	)
+
+	// Show health status of the node as HTML report
+	ShowHealthNode = show.ContraMap[report, types.StatusNode]{T: showReport}.FMap(
+		func(n types.StatusNode) report {
+			return reportOf("health of "+n.Node.Name, nodeOf(n.Node.Cluster, roleOf(n), n))
+		},
+	)
+
+	// Show resource utilization of the node as HTML report
+	ShowValueNode = show.ContraMap[report, types.StatusNode]{T: showReport}.FMap(
+		func(n types.StatusNode) report {
+			return reportOf("resource utilization of "+n.Node.Name, nodeOf(n.Node.Cluster, roleOf(n), n))
+		},
+	)
+
+	// Show health status of the region as HTML report
+	ShowHealthRegion = show.ContraMap[report, types.StatusRegion]{T: showReport}.FMap(
+		func(r types.StatusRegion) report {
+			nodes := make([]node, 0)
+			for _, c := range r.Clusters {
+				for _, n := range c.Writer {
+					nodes = append(nodes, nodeOf(c.Cluster.ID, "writer", n))
+				}
+				for _, n := range c.Reader {
+					nodes = append(nodes, nodeOf(c.Cluster.ID, "reader", n))
+				}
+			}
+			for _, n := range r.Nodes {
+				nodes = append(nodes, nodeOf("", "instance", n))
+			}
+
+			// failing instances first
+			slices.SortStableFunc(nodes, func(a, b node) int { return int(b.Status) - int(a.Status) })
+
+			report := reportOf("health of the region", nodes...)
+			for _, c := range r.Clusters {
+				if len(c.Checks) != 0 {
+					report.Clusters = append(report.Clusters, clusterOf(c))
+				}
+			}
+			return report
+		},
+	)
+)
@@ -0,0 +1,133 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package html_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/show/html"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func series(avg ...float64) *types.Series {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &types.Series{Metric: "os.cpuUtilization.total"}
+	for i, x := range avg {
+		s.Time = append(s.Time, t0.Add(time.Duration(i)*time.Minute))
+		s.Min = append(s.Min, x)
+		s.Avg = append(s.Avg, x)
+		s.Max = append(s.Max, x)
+	}
+	return s
+}
+
+func node(name string, checks ...types.Status) types.StatusNode {
+	status := types.STATUS_CODE_UNKNOWN
+	for _, s := range checks {
+		status = max(status, s.Code)
+	}
+	return types.StatusNode{Status: status, Node: &types.Node{Name: name}, Checks: checks}
+}
+
+func TestShowHealthRegion(t *testing.T) {
+	pass := types.Status{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1", About: "cpu utilization"}}
+	fail := types.Status{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "D3", About: "storage i/o latency"}}
+
+	for name, tt := range map[string]struct {
+		region types.StatusRegion
+		has    []string
+		hasNot []string
+	}{
+		"escaping": {
+			region: types.StatusRegion{Nodes: []types.StatusNode{
+				node("<script>alert(1)</script>", types.Status{Rule: types.Rule{About: "a & b"}}),
+			}},
+			has:    []string{"&lt;script&gt;alert(1)&lt;/script&gt;", "a &amp; b"},
+			hasNot: []string{"<script>"},
+		},
+		"failing rules": {
+			region: types.StatusRegion{Nodes: []types.StatusNode{node("a", pass, fail)}},
+			has:    []string{`<a href="#node-a">a</a>`, "<td>D3</td>", `<span class="badge fail">FAIL</span>`},
+		},
+		"cluster status of cluster rules": {
+			region: types.StatusRegion{Clusters: []types.StatusCluster{{
+				Status:  types.STATUS_CODE_FAILURE,
+				Cluster: &types.Cluster{ID: "c"},
+				Writer:  []types.StatusNode{node("a", fail)},
+				Checks:  []types.Status{{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "R1", About: "writer"}}},
+			}}},
+			has: []string{`<h2 id="cluster-c"><span class="badge pass">PASS</span> c</h2>`},
+		},
+		"cluster without rules": {
+			region: types.StatusRegion{Clusters: []types.StatusCluster{{
+				Status:  types.STATUS_CODE_FAILURE,
+				Cluster: &types.Cluster{ID: "c"},
+				Writer:  []types.StatusNode{node("a", fail)},
+			}}},
+			hasNot: []string{`id="cluster-c"`},
+		},
+		"chart with thresholds": {
+			region: types.StatusRegion{Nodes: []types.StatusNode{node("a", types.Status{
+				Code:      types.STATUS_CODE_WARNING,
+				Rule:      types.Rule{ID: "C1", About: "cpu utilization"},
+				Series:    series(10, 20, 30),
+				Threshold: &types.Threshold{Op: "below", Avg: 40, Peak: 60},
+			})}},
+			has: []string{"<svg", `<polyline class="avg"`, "avg threshold 40", "max threshold 60"},
+		},
+		"no chart without samples": {
+			region: types.StatusRegion{Nodes: []types.StatusNode{node("a", types.Status{
+				Rule:   types.Rule{ID: "C1", About: "cpu utilization"},
+				Series: series(math.NaN(), math.NaN()),
+			})}},
+			hasNot: []string{"<svg"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := html.ShowHealthRegion.Show(tt.region)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, s := range tt.has {
+				if !strings.Contains(string(b), s) {
+					t.Errorf("should contain %s", s)
+				}
+			}
+			for _, s := range tt.hasNot {
+				if strings.Contains(string(b), s) {
+					t.Errorf("should not contain %s", s)
+				}
+			}
+		})
+	}
+}
+
+func TestChartSegments(t *testing.T) {
+	b, err := html.ShowHealthNode.Show(node("a", types.Status{
+		Rule:   types.Rule{ID: "C1", About: "cpu utilization"},
+		Series: series(10, math.NaN(), 30, 40),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(string(b), `<polyline class="avg"`); n != 2 {
+		t.Errorf("should split avg line into 2 segments, got %d", n)
+	}
+	if n := strings.Count(string(b), `<polygon class="band"`); n != 2 {
+		t.Errorf("should split min-max band into 2 segments, got %d", n)
+	}
+	if strings.Contains(string(b), "NaN") {
+		t.Errorf("should skip missing samples")
+	}
+}
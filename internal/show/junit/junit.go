@@ -0,0 +1,217 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// Show health status as JUnit XML report, each node is a test suite and
+// each rule is a test case, cluster rules are test cases of the cluster.
+// Rules that warn or fail are failed test cases, rules with unknown status
+// are skipped.
+//
+
+type testsuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Suites   []testsuite `xml:"testsuite"`
+}
+
+type testsuite struct {
+	Name       string     `xml:"name,attr"`
+	Tests      int        `xml:"tests,attr"`
+	Failures   int        `xml:"failures,attr"`
+	Skipped    int        `xml:"skipped,attr"`
+	Properties []property `xml:"properties>property,omitempty"`
+	Cases      []testcase `xml:"testcase"`
+}
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type testcase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Failure   *failure `xml:"failure,omitempty"`
+	Skipped   *skipped `xml:"skipped,omitempty"`
+}
+
+type failure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type skipped struct{}
+
+func suiteOf(cluster string, n types.StatusNode) testsuite {
+	name := n.Node.Name
+	if cluster != "" {
+		name = cluster + "/" + name
+	}
+
+	suite := casesOf(name, n.Checks)
+
+	suite.Properties = append(suite.Properties, property{"class", n.Node.Type})
+	if n.Node.Engine != nil {
+		suite.Properties = append(suite.Properties,
+			property{"engine", n.Node.Engine.ID},
+			property{"version", n.Node.Engine.Version},
+		)
+	}
+
+	return suite
+}
+
+// cluster rules are test cases of the cluster's test suite
+func clusterSuiteOf(c types.StatusCluster) testsuite {
+	suite := casesOf(c.Cluster.ID, c.Checks)
+	if c.Cluster.Engine != nil {
+		suite.Properties = append(suite.Properties,
+			property{"engine", c.Cluster.Engine.ID},
+			property{"version", c.Cluster.Engine.Version},
+		)
+	}
+
+	return suite
+}
+
+func casesOf(name string, checks []types.Status) testsuite {
+	suite := testsuite{Name: name, Cases: make([]testcase, 0, len(checks))}
+
+	for _, s := range checks {
+		tc := testcase{Name: s.Rule.String(), ClassName: "rds-health." + name}
+		tc.Name = strings.TrimSpace(tc.Name)
+
+		switch s.Code {
+		case types.STATUS_CODE_UNKNOWN:
+			tc.Skipped = &skipped{}
+			suite.Skipped++
+		case types.STATUS_CODE_WARNING, types.STATUS_CODE_FAILURE:
+			tc.Failure = &failure{
+				Type:    strings.ToLower(s.Code.String()),
+				Message: message(s),
+				Text:    details(s),
+			}
+			suite.Failures++
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	return suite
+}
+
+// one line summary of the failure
+func message(s types.Status) string {
+	text := fmt.Sprintf("%s %s", s.Code, s.Rule.About)
+
+	switch {
+	case s.SoftMM != nil && s.Threshold != nil && s.Threshold.Op == "above":
+		text += fmt.Sprintf(": avg %.2f %s, min %.2f %s", s.SoftMM.Avg, s.Rule.Unit, s.SoftMM.Min, s.Rule.Unit)
+	case s.SoftMM != nil:
+		text += fmt.Sprintf(": avg %.2f %s, max %.2f %s", s.SoftMM.Avg, s.Rule.Unit, s.SoftMM.Max, s.Rule.Unit)
+	case s.Observed != nil:
+		text += ": " + *s.Observed
+	}
+
+	if s.Threshold != nil {
+		text += " (threshold " + s.Threshold.String() + ")"
+	}
+
+	return text
+}
+
+// observed values of the failure
+func details(s types.Status) string {
+	seq := []string{fmt.Sprintf("status: %s", s.Code)}
+
+	if s.SoftMM != nil {
+		seq = append(seq, fmt.Sprintf("soft min/avg/max: %.2f / %.2f / %.2f %s", s.SoftMM.Min, s.SoftMM.Avg, s.SoftMM.Max, s.Rule.Unit))
+	}
+
+	if s.Threshold != nil {
+		seq = append(seq, fmt.Sprintf("threshold: %s", s.Threshold))
+	}
+
+	if s.SuccessRate != nil {
+		seq = append(seq, fmt.Sprintf("success rate: %.2f%%", *s.SuccessRate))
+	}
+
+	if s.Observed != nil {
+		seq = append(seq, fmt.Sprintf("observed: %s", *s.Observed))
+	}
+
+	if s.Interval != 0 {
+		seq = append(seq, fmt.Sprintf("sampling interval: %s", s.Interval))
+	}
+
+	return strings.Join(seq, "\n")
+}
+
+func suitesOf(suites ...testsuite) testsuites {
+	report := testsuites{Name: "rds-health", Suites: suites}
+	for _, s := range suites {
+		report.Tests += s.Tests
+		report.Failures += s.Failures
+		report.Skipped += s.Skipped
+	}
+	return report
+}
+
+var (
+	showReport = show.FromShow[testsuites](
+		func(report testsuites) ([]byte, error) {
+			b, err := xml.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+
+			return append(append([]byte(xml.Header), b...), '\n'), nil
+		},
+	)
+
+	// Show health status of the node as JUnit XML report
+	ShowHealthNode = show.ContraMap[testsuites, types.StatusNode]{T: showReport}.FMap(
+		func(n types.StatusNode) testsuites { return suitesOf(suiteOf(n.Node.Cluster, n)) },
+	)
+
+	// Show health status of the region as JUnit XML report
+	ShowHealthRegion = show.ContraMap[testsuites, types.StatusRegion]{T: showReport}.FMap(
+		func(r types.StatusRegion) testsuites {
+			suites := make([]testsuite, 0)
+			for _, c := range r.Clusters {
+				for _, n := range slices.Concat(c.Writer, c.Reader) {
+					suites = append(suites, suiteOf(c.Cluster.ID, n))
+				}
+				if len(c.Checks) != 0 {
+					suites = append(suites, clusterSuiteOf(c))
+				}
+			}
+			for _, n := range r.Nodes {
+				suites = append(suites, suiteOf("", n))
+			}
+			return suitesOf(suites...)
+		},
+	)
+)
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package junit_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/zalando/rds-health/internal/show/junit"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+type report struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Skipped  int `xml:"skipped,attr"`
+	Suites   []struct {
+		Name     string `xml:"name,attr"`
+		Tests    int    `xml:"tests,attr"`
+		Failures int    `xml:"failures,attr"`
+		Skipped  int    `xml:"skipped,attr"`
+		Cases    []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Type    string `xml:"type,attr"`
+				Message string `xml:"message,attr"`
+			} `xml:"failure"`
+			Skipped *struct{} `xml:"skipped"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func region() types.StatusRegion {
+	rate, observed := 80.0, "eu-central-1a"
+
+	node := types.StatusNode{
+		Status: types.STATUS_CODE_FAILURE,
+		Node:   &types.Node{Name: "a", Type: "db.r6g.large", Engine: &types.Engine{ID: "aurora-postgresql", Version: "15.4"}},
+		Checks: []types.Status{
+			{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1", Unit: "%", About: "cpu utilization"}},
+			{Code: types.STATUS_CODE_WARNING, Rule: types.Rule{ID: "C2", Unit: "%", About: "cpu i/o wait"}, SuccessRate: &rate, SoftMM: &types.MinMax{Min: 1, Avg: 6, Max: 20}},
+			{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "D3", Unit: "ms", About: "storage i/o latency"}, SoftMM: &types.MinMax{Min: 2, Avg: 12, Max: 40}},
+			{Code: types.STATUS_CODE_UNKNOWN, Rule: types.Rule{ID: "P1", About: "sql efficiency"}},
+		},
+	}
+
+	return types.StatusRegion{
+		Status: types.STATUS_CODE_FAILURE,
+		Clusters: []types.StatusCluster{
+			{
+				Status:  types.STATUS_CODE_FAILURE,
+				Cluster: &types.Cluster{ID: "c"},
+				Writer:  []types.StatusNode{node},
+				Checks: []types.Status{
+					{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "R4", About: "members in single zone"}, Observed: &observed},
+				},
+			},
+		},
+	}
+}
+
+func TestShowHealthRegion(t *testing.T) {
+	b, err := junit.ShowHealthRegion.Show(region())
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	var r report
+	if err := xml.Unmarshal(b, &r); err != nil {
+		t.Fatalf("should produce valid xml %s", err)
+	}
+
+	switch {
+	case r.Tests != 5 || r.Failures != 3 || r.Skipped != 1:
+		t.Errorf("unexpected totals |%+v|", r)
+	case len(r.Suites) != 2:
+		t.Fatalf("unexpected suites |%+v|", r.Suites)
+	}
+
+	node, cluster := r.Suites[0], r.Suites[1]
+	switch {
+	case node.Name != "c/a" || node.Tests != 4 || node.Failures != 2 || node.Skipped != 1:
+		t.Errorf("unexpected suite of node |%+v|", node)
+	case cluster.Name != "c" || cluster.Tests != 1 || cluster.Failures != 1:
+		t.Errorf("unexpected suite of cluster |%+v|", cluster)
+	}
+
+	for i, expected := range []string{"", "warned", "failed", ""} {
+		tc := node.Cases[i]
+		switch {
+		case expected == "" && tc.Failure != nil:
+			t.Errorf("unexpected failure of %s", tc.Name)
+		case expected != "" && (tc.Failure == nil || tc.Failure.Type != expected):
+			t.Errorf("unexpected failure of %s |%+v|", tc.Name, tc.Failure)
+		}
+	}
+
+	if node.Cases[3].Skipped == nil {
+		t.Errorf("unknown rule should be skipped")
+	}
+
+	if f := cluster.Cases[0].Failure; f == nil || f.Message != "FAILED members in single zone: eu-central-1a" {
+		t.Errorf("unexpected failure of cluster |%+v|", f)
+	}
+}
+
+func TestShowHealthNode(t *testing.T) {
+	node := region().Clusters[0].Writer[0]
+	node.Node.Cluster = "c"
+
+	b, err := junit.ShowHealthNode.Show(node)
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	var r report
+	if err := xml.Unmarshal(b, &r); err != nil {
+		t.Fatalf("should produce valid xml %s", err)
+	}
+
+	if r.Tests != 4 || r.Failures != 2 || r.Skipped != 1 || len(r.Suites) != 1 || r.Suites[0].Name != "c/a" {
+		t.Errorf("unexpected report |%+v|", r)
+	}
+}
@@ -11,7 +11,10 @@ package minimal
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/lynn9388/supsub"
 	"github.com/zalando/rds-health/internal/show"
@@ -27,16 +30,17 @@ var (
 	// 1a postgres 14.7 db.m5.large 2x 8 GiB 100 GiB gp2 ro example-database-a
 	showConfigNode = show.FromShow[types.Node](
 		func(n types.Node) ([]byte, error) {
-			zone := ""
-			if len(n.Zones) > 0 {
-				zone = n.Zones[0]
+			az := ""
+			if len(n.Zones) > 0 && len(n.Zones[0]) >= 2 {
+				az = n.Zones[0][len(n.Zones[0])-2:]
 			}
-			az := zone[len(zone)-2:]
 
 			cpu := "-"
 			mem := "-"
-			if n.Compute != nil {
+			if n.Compute != nil && n.Compute.CPU != nil {
 				cpu = fmt.Sprintf("%dx", n.Compute.CPU.Cores)
+			}
+			if n.Compute != nil && n.Compute.Memory != nil {
 				mem = n.Compute.Memory.Size.String()
 			}
 
@@ -78,6 +82,59 @@ var (
 	)
 )
 
+//
+// Show config and health status about groups of Nodes and Clusters
+//
+
+var (
+	// Show the group as one liner
+	// team: payments
+	ShowGroup = show.FromShow[types.Group](
+		func(g types.Group) ([]byte, error) {
+			value := g.Value
+			if value == "" {
+				value = "(none)"
+			}
+
+			text := fmt.Sprintf("\n"+show.SCHEMA.Cluster+"\n", g.Key+": "+value)
+			return []byte(text), nil
+		},
+	)
+
+	// Show config of all instances in region, grouped by the tag
+	ShowConfigRegionGroups = show.Seq[types.RegionGroup]{
+		T: show.Printer2[types.RegionGroup, types.Group, types.Region]{
+			A: ShowGroup,
+			B: ShowConfigRegion,
+			UnApply2: func(g types.RegionGroup) (types.Group, types.Region) {
+				return g.Group, g.Region
+			},
+		},
+	}
+
+	// Show health of region grouped by the tag, each group is followed by its summary
+	ShowHealthRegionGroups = show.Seq[types.StatusRegionGroup]{
+		T: show.Printer2[types.StatusRegionGroup, types.Group, types.StatusRegion]{
+			A: ShowGroup,
+			B: ShowHealthRegion,
+			UnApply2: func(g types.StatusRegionGroup) (types.Group, types.StatusRegion) {
+				return g.Group, g.StatusRegion
+			},
+		},
+	}
+
+	// Show enhanced health of region grouped by the tag
+	ShowHealthRegionWithRulesGroups = show.Seq[types.StatusRegionGroup]{
+		T: show.Printer2[types.StatusRegionGroup, types.Group, types.StatusRegion]{
+			A: ShowGroup,
+			B: ShowHealthRegionWithRules,
+			UnApply2: func(g types.StatusRegionGroup) (types.Group, types.StatusRegion) {
+				return g.Group, g.StatusRegion
+			},
+		},
+	}
+)
+
 //
 // Show health status about Nodes, Clusters, Regions
 //
@@ -171,54 +228,30 @@ var (
 		},
 	}
 
-	// Show cluster health status as one line
-	// PASS example-cluster
+	// Show cluster health status as one line, including cluster rules that do not pass
+	// FAIL example-cluster ¦ R1 R⁴
 	showHealthCluster = show.FromShow[types.StatusCluster](
 		func(c types.StatusCluster) ([]byte, error) {
 			status := show.StatusText(c.Status)
-			text := fmt.Sprintf("%s "+show.SCHEMA.Cluster+"\n", status, c.Cluster.ID)
+			text := fmt.Sprintf("%s "+show.SCHEMA.Cluster+"%s\n", status, c.Cluster.ID, clusterRules(c.Checks))
 			return []byte(text), nil
 		},
 	)
 
 	// Show cluster health as one line including the formatting for rules
-	// PASS example-cluster
+	// FAIL                                     example-cluster ¦ R1 R⁴
 	showHealthClusterWithRules = show.FromShow[types.StatusCluster](
 		func(c types.StatusCluster) ([]byte, error) {
 			status := show.StatusText(c.Status)
 
-			text := fmt.Sprintf("%s %35s "+show.SCHEMA.Cluster+"\n", status, "", c.Cluster.ID)
+			text := fmt.Sprintf("%s %35s "+show.SCHEMA.Cluster+"%s\n", status, "", c.Cluster.ID, clusterRules(c.Checks))
 			return []byte(text), nil
 		},
 	)
 
 	// Show region health status as one line
 	// PASS 14 health checks
-	showHealthRegion = show.FromShow[types.StatusRegion](
-		func(r types.StatusRegion) ([]byte, error) {
-			nall := len(r.Clusters) + len(r.Nodes)
-			pass := 0
-			for _, c := range r.Clusters {
-				if c.Status <= types.STATUS_CODE_SUCCESS {
-					pass++
-				}
-			}
-
-			for _, n := range r.Nodes {
-				if n.Status <= types.STATUS_CODE_SUCCESS {
-					pass++
-				}
-			}
-
-			if nall == pass {
-				text := fmt.Sprintf("\n%s%s %d health checks\n", show.StatusIcon(r.Status), show.StatusText(r.Status), nall)
-				return []byte(text), nil
-			}
-
-			text := fmt.Sprintf("\n%s%s %d health checks (%d passed)\n", show.StatusIcon(r.Status), show.StatusText(r.Status), nall-pass, pass)
-			return []byte(text), nil
-		},
-	)
+	showHealthRegion = showSummaryRegion("health checks")
 
 	// Show health of cluster and its nodes, one line per node
 	ShowHealthCluster = show.Cluster(
@@ -234,21 +267,22 @@ var (
 		func(sr types.StatusRegion) ([]types.StatusCluster, []types.StatusNode) { return sr.Clusters, sr.Nodes },
 	)
 
-	// Show health of clusters and nodes in the region, including status for each rule
-	showHealthRegionMembersWithRules = show.Prefix[types.StatusRegion](
-		"     C1 C2 M1 M2 D1 D2 D3 P1 P2 P3 P4 P5\n",
-	).FMap(
-		show.Region[types.StatusRegion](
-			show.Cluster(
-				showHealthClusterWithRules,
-				showHealthNodeWithRules,
-				func(sc types.StatusCluster) ([]types.StatusNode, []types.StatusNode) { return sc.Writer, sc.Reader },
-			),
+	// Show health of clusters and nodes in the region, status for each rule
+	showHealthRegionMembersRules = show.Region[types.StatusRegion](
+		show.Cluster(
+			showHealthClusterWithRules,
 			showHealthNodeWithRules,
-			func(sr types.StatusRegion) ([]types.StatusCluster, []types.StatusNode) { return sr.Clusters, sr.Nodes },
+			func(sc types.StatusCluster) ([]types.StatusNode, []types.StatusNode) { return sc.Writer, sc.Reader },
 		),
+		showHealthNodeWithRules,
+		func(sr types.StatusRegion) ([]types.StatusCluster, []types.StatusNode) { return sr.Clusters, sr.Nodes },
 	)
 
+	// Show health of clusters and nodes in the region, including status for each rule
+	showHealthRegionMembersWithRules = show.Prefix[types.StatusRegion](
+		"     C1 C2 M1 M2 D1 D2 D3 P1 P2 P3 P4 P5\n",
+	).FMap(showHealthRegionMembersRules)
+
 	// Show health of region and its objects
 	ShowHealthRegion = show.Printer2[types.StatusRegion, types.StatusRegion, types.StatusRegion]{
 		A: showHealthRegionMembers,
@@ -268,6 +302,104 @@ var (
 	}
 )
 
+// Show region status as one line, counting nodes and clusters
+// PASS 14 health checks
+func showSummaryRegion(label string) show.Printer[types.StatusRegion] {
+	return show.FromShow[types.StatusRegion](
+		func(r types.StatusRegion) ([]byte, error) {
+			nall := len(r.Clusters) + len(r.Nodes)
+			pass := 0
+			for _, c := range r.Clusters {
+				if c.Status <= types.STATUS_CODE_SUCCESS {
+					pass++
+				}
+			}
+
+			for _, n := range r.Nodes {
+				if n.Status <= types.STATUS_CODE_SUCCESS {
+					pass++
+				}
+			}
+
+			if nall == pass {
+				text := fmt.Sprintf("\n%s%s %d %s\n", show.StatusIcon(r.Status), show.StatusText(r.Status), nall, label)
+				return []byte(text), nil
+			}
+
+			text := fmt.Sprintf("\n%s%s %d %s (%d passed)\n", show.StatusIcon(r.Status), show.StatusText(r.Status), nall-pass, label, pass)
+			return []byte(text), nil
+		},
+	)
+}
+
+//
+// Show audit status about Nodes, Clusters, Regions
+//
+
+var (
+	// Show the status of single audit rule
+	// FAILED A1: multi-az deployment              single-az
+	showAuditRule = show.FromShow[types.Status](
+		func(status types.Status) ([]byte, error) {
+			if status.Code > types.STATUS_CODE_SUCCESS {
+				observed := "-"
+				if status.Observed != nil {
+					observed = *status.Observed
+				}
+
+				ffs := show.SCHEMA.FmtForStatus(status.Code)
+				text := fmt.Sprintf(ffs+" %s: %-37s %s\n", status.Code, status.Rule.ID, status.Rule.About, observed)
+				return []byte(text), nil
+			}
+
+			return nil, nil
+		},
+	)
+
+	// Show audit status of node and all failed rules
+	// STATUS ID CHECK                                 OBSERVED
+	// FAILED A1: multi-az deployment                  single-az
+	// WARNED A4: deletion protection                  disabled
+	//
+	// ❌ FAIL example-database
+	//
+	ShowAuditNode = show.Printer2[types.StatusNode, []types.Status, types.StatusNode]{
+		A: show.Prefix[[]types.Status](
+			fmt.Sprintf("%6s %-41s %s\n", "STATUS", "ID CHECK", "OBSERVED"),
+		).FMap(show.Seq[types.Status]{T: showAuditRule}),
+		B: showHealthNodeWithSymbol,
+		UnApply2: func(sn types.StatusNode) ([]types.Status, types.StatusNode) {
+			return sn.Checks, sn
+		},
+	}
+
+	// Show region audit status as one line
+	// PASS 14 audits
+	showAuditRegion = showSummaryRegion("audits")
+
+	// Show audit of region and its objects
+	ShowAuditRegion = show.Printer2[types.StatusRegion, types.StatusRegion, types.StatusRegion]{
+		A: showHealthRegionMembers,
+		B: showAuditRegion,
+		UnApply2: func(sr types.StatusRegion) (types.StatusRegion, types.StatusRegion) {
+			return sr, sr
+		},
+	}
+)
+
+// Show enhanced audit of region and its objects, including status for each rule
+func ShowAuditRegionWithRules(ids []string) show.Printer[types.StatusRegion] {
+	return show.Printer2[types.StatusRegion, types.StatusRegion, types.StatusRegion]{
+		A: show.Prefix[types.StatusRegion](
+			"     " + strings.Join(ids, " ") + "\n",
+		).FMap(showHealthRegionMembersRules),
+		B: showAuditRegion,
+		UnApply2: func(sr types.StatusRegion) (types.StatusRegion, types.StatusRegion) {
+			return sr, sr
+		},
+	}
+}
+
 //
 // Show Values of Rules
 //
@@ -303,4 +435,367 @@ var (
 			UnApply2: func(sn types.StatusNode) ([]types.Status, types.StatusNode) { return sn.Checks, sn },
 		},
 	)
+
+	// Show soft average of members side by side with cluster totals
+	// UNIT   my-cluster-1   my-cluster-2          TOTAL
+	//  tps          44.88          12.10          56.98 db transactions (xact_commit)
+	//
+	// my-cluster (aurora-postgresql v14.7), writer my-cluster-1, reader my-cluster-2
+	ShowValueCluster = show.FromShow[types.StatusCluster](
+		func(c types.StatusCluster) ([]byte, error) {
+			members := slices.Concat(c.Writer, c.Reader)
+			width := make([]int, len(members))
+
+			b := &bytes.Buffer{}
+			b.WriteString("UNIT")
+			for i, m := range members {
+				width[i] = max(14, len(m.Node.Name))
+				b.WriteString(fmt.Sprintf(" %*s", width[i], m.Node.Name))
+			}
+			b.WriteString(fmt.Sprintf(" %14s\n", "TOTAL"))
+
+			for _, total := range c.Checks {
+				b.WriteString(fmt.Sprintf("%4s", total.Rule.Unit))
+				for i, m := range members {
+					b.WriteString(fmt.Sprintf(" %*.2f", width[i], softAvgOf(m.Checks, total.Rule)))
+				}
+				b.WriteString(fmt.Sprintf(" %14.2f %s\n", softAvgOf(c.Checks, total.Rule), total.Rule.About))
+			}
+
+			names := func(seq []types.StatusNode) string {
+				s := make([]string, len(seq))
+				for i, n := range seq {
+					s[i] = n.Node.Name
+				}
+				return strings.Join(s, ", ")
+			}
+
+			b.WriteString(fmt.Sprintf("\n"+show.SCHEMA.Cluster, c.Cluster.ID))
+			if c.Cluster.Engine != nil {
+				b.WriteString(fmt.Sprintf(" (%s)", c.Cluster.Engine))
+			}
+			b.WriteString(fmt.Sprintf(", writer %s", names(c.Writer)))
+			if len(c.Reader) != 0 {
+				b.WriteString(fmt.Sprintf(", reader %s", names(c.Reader)))
+			}
+			b.WriteString("\n")
+
+			return b.Bytes(), nil
+		},
+	)
+
+	// Show soft average of metrics, one row per node
+	// NODE         CLUSTER    os.cpuUtilization.total (%)
+	// my-cluster-1 my-cluster                       35.20
+	ShowValueRegion = show.FromShow[types.StatusRegion](
+		func(r types.StatusRegion) ([]byte, error) {
+			type row struct {
+				cluster string
+				node    types.StatusNode
+			}
+
+			rows := make([]row, 0)
+			for _, c := range r.Clusters {
+				for _, n := range slices.Concat(c.Writer, c.Reader) {
+					rows = append(rows, row{c.Cluster.ID, n})
+				}
+			}
+			for _, n := range r.Nodes {
+				rows = append(rows, row{"-", n})
+			}
+
+			if len(rows) == 0 {
+				return nil, nil
+			}
+
+			wnode, wcluster := len("NODE"), len("CLUSTER")
+			for _, x := range rows {
+				wnode, wcluster = max(wnode, len(x.node.Node.Name)), max(wcluster, len(x.cluster))
+			}
+
+			// metrics are same for all nodes, the header is defined by the first one
+			header := rows[0].node.Checks
+			width := make([]int, len(header))
+
+			b := &bytes.Buffer{}
+			b.WriteString(fmt.Sprintf("%-*s %-*s", wnode, "NODE", wcluster, "CLUSTER"))
+			for i, s := range header {
+				title := s.Rule.About
+				if s.Series != nil {
+					title = s.Series.Metric
+				}
+				title = fmt.Sprintf("%s (%s)", title, s.Rule.Unit)
+				width[i] = max(10, len(title))
+				b.WriteString(fmt.Sprintf(" %*s", width[i], title))
+			}
+			b.WriteString("\n")
+
+			for _, x := range rows {
+				b.WriteString(fmt.Sprintf("%-*s %-*s", wnode, x.node.Node.Name, wcluster, x.cluster))
+				for i, s := range header {
+					b.WriteString(fmt.Sprintf(" %*.2f", width[i], softAvgOf(x.node.Checks, s.Rule)))
+				}
+				b.WriteString("\n")
+			}
+
+			return b.Bytes(), nil
+		},
+	)
+)
+
+// soft average of the rule, NaN if it is not observed
+func softAvgOf(checks []types.Status, rule types.Rule) float64 {
+	for _, s := range checks {
+		if s.Rule == rule && s.SoftMM != nil {
+			return s.SoftMM.Avg
+		}
+	}
+	return math.NaN()
+}
+
+//
+// Show rightsize recommendations
+//
+
+// utilization as percent, "-" if the metric is not observed
+func showUtilization(seq []types.Utilization) string {
+	if len(seq) == 0 {
+		return fmt.Sprintf("%6s %6s %6s", "-", "-", "-")
+	}
+
+	text := make([]string, len(seq))
+	for i, u := range seq {
+		text[i] = fmt.Sprintf("%6s", "-")
+		if u.Observed {
+			text[i] = fmt.Sprintf("%5.1f%%", u.Value)
+		}
+	}
+	return strings.Join(text, " ")
+}
+
+var (
+	// Show sizing of the node using the class as one liner
+	// downsize db.r6g.large      2x   16 GiB  24.0%  40.0%  12.5%
+	showSizing = show.FromShow[types.Sizing](
+		func(s types.Sizing) ([]byte, error) {
+			cpu, mem := "-", "-"
+			if s.Compute != nil {
+				cpu = fmt.Sprintf("%dx", s.Compute.CPU.Cores)
+				mem = s.Compute.Memory.Size.String()
+			}
+
+			text := fmt.Sprintf("%-8s %-17s %4s %8s %s\n", s.Action, s.Class, cpu, mem, showUtilization(s.Utilization))
+			return []byte(text), nil
+		},
+	)
+
+	// Show short information about node
+	showInfoRightsize = show.FromShow[types.Rightsize](
+		func(r types.Rightsize) ([]byte, error) {
+			text := fmt.Sprintf("\n%s (%s, %s)\n", r.Node.Name, r.Node.Type, r.Node.Engine)
+			if len(r.Options) == 0 {
+				text = fmt.Sprintf("\n%s (%s, %s): unknown instance class\n", r.Node.Name, r.Node.Type, r.Node.Engine)
+			}
+			return []byte(text), nil
+		},
+	)
+
+	// Show rightsize options of the node
+	// ACTION   CLASS              CPU      MEM   CPU%  LOAD%   MEM%
+	// current  db.r5.2xlarge       8x   64 GiB   6.0%  10.0%   3.1%
+	// downsize db.r5.large         2x   16 GiB  24.0%  40.0%  12.5%
+	// change   db.r6g.large        2x   16 GiB  24.0%  40.0%  12.5%
+	//
+	// example-database (db.r5.2xlarge, postgres v14.7)
+	ShowRightsizeNode = show.Prefix[types.Rightsize](
+		fmt.Sprintf("%-8s %-17s %4s %8s %6s %6s %6s\n", "ACTION", "CLASS", "CPU", "MEM", "CPU%", "LOAD%", "MEM%"),
+	).FMap(
+		show.Printer2[types.Rightsize, []types.Sizing, types.Rightsize]{
+			A:        show.Seq[types.Sizing]{T: showSizing},
+			B:        showInfoRightsize,
+			UnApply2: func(r types.Rightsize) ([]types.Sizing, types.Rightsize) { return r.Options, r },
+		},
+	)
+
+	// Show recommended sizing of the node as one liner
+	// db.r5.2xlarge   → db.r6g.large      change    24.0%  40.0%  12.5% example-database
+	showRightsize = show.FromShow[types.Rightsize](
+		func(r types.Rightsize) ([]byte, error) {
+			s := r.Recommended()
+			if len(r.Options) == 0 {
+				s.Class = "-"
+			}
+
+			text := fmt.Sprintf("%-17s → %-17s %-8s %s %s\n", r.Node.Type, s.Class, s.Action, showUtilization(s.Utilization), r.Node.Name)
+			return []byte(text), nil
+		},
+	)
+
+	// Show recommended sizing of all instances in region
+	ShowRightsizeRegion = show.Prefix[[]types.Rightsize](
+		fmt.Sprintf("%-17s   %-17s %-8s %6s %6s %6s %s\n", "INSTANCE", "RECOMMENDED", "ACTION", "CPU%", "LOAD%", "MEM%", "NAME"),
+	).FMap(
+		show.Seq[types.Rightsize]{T: showRightsize},
+	)
+)
+
+//
+// Show transitions of health status
+//
+
+var (
+	// Show transition of health status as one liner
+	// 2024-05-01T10:00:00Z PASS → WARN example-database-a
+	// 2024-05-01T10:00:00Z PASS → WARN example-database-a D3: storage i/o latency (avg 12.50 ms)
+	ShowTransition = show.FromShow[types.Transition](
+		func(t types.Transition) ([]byte, error) {
+			text := fmt.Sprintf("%s %s → %s %s", t.Time.Format(time.RFC3339), show.StatusText(t.From), show.StatusText(t.To), t.Node)
+
+			if t.Rule != nil {
+				text += fmt.Sprintf(" %s: %s", t.Rule.ID, t.Rule.About)
+			}
+
+			if t.Status != nil && t.Status.SoftMM != nil {
+				text += fmt.Sprintf(" (avg %.2f %s)", t.Status.SoftMM.Avg, t.Rule.Unit)
+			}
+
+			return []byte(text + "\n"), nil
+		},
+	)
+
+	// Show transitions, one per line
+	ShowTransitions = show.Seq[types.Transition]{T: ShowTransition}
+
+	// Show change of health status since the baseline as one liner
+	// failure  PASS → FAIL example-database-a D3: storage i/o latency (success rate 100.00 → 67.86 %, avg 3.10 → 13.33 ms)
+	// added    NONE → PASS example-database-b
+	ShowChange = show.FromShow[types.Change](
+		func(c types.Change) ([]byte, error) {
+			text := fmt.Sprintf("%-8s %s → %s %s", c.Kind, show.StatusText(c.From), show.StatusText(c.To), c.Node)
+
+			if c.Rule != nil {
+				text += fmt.Sprintf(" %s: %s", c.Rule.ID, c.Rule.About)
+			}
+
+			if len(c.Deltas) != 0 {
+				seq := make([]string, len(c.Deltas))
+				for i, d := range c.Deltas {
+					unit := c.Rule.Unit
+					if d.Value == "success rate" {
+						unit = "%"
+					}
+					seq[i] = fmt.Sprintf("%s %.2f → %.2f %s", d.Value, d.From, d.To, unit)
+				}
+				text += " (" + strings.Join(seq, ", ") + ")"
+			}
+
+			return []byte(text + "\n"), nil
+		},
+	)
+
+	// Show changes, one per line
+	ShowChanges = show.Seq[types.Change]{T: ShowChange}
+
+	// Show history of node health status, one check per line with status
+	// of each rule, followed by rules that do not pass
+	// TIME              STATUS SCORE  C1 C2 D3
+	// 2024-05-01 10:00  FAIL     67%  P  P  F
+	// 2024-05-01 11:00  FAIL     67%  P  P  F
+	//
+	// FAIL D3: storage i/o latency for 1h 0m since 2024-05-01 10:00 (2 checks)
+	ShowHistory = show.FromShow[types.History](
+		func(h types.History) ([]byte, error) {
+			ids := make([]string, 0)
+			for _, r := range h.Records {
+				for _, c := range r.Checks {
+					if !slices.Contains(ids, c.Rule.ID) {
+						ids = append(ids, c.Rule.ID)
+					}
+				}
+			}
+
+			b := &bytes.Buffer{}
+			b.WriteString(fmt.Sprintf("%-17s %-6s %5s ", "TIME", "STATUS", "SCORE"))
+			for _, id := range ids {
+				b.WriteString(fmt.Sprintf(" %-2s", id))
+			}
+			b.WriteString("\n")
+
+			for _, r := range h.Records {
+				score := "-"
+				if r.Score != nil {
+					score = fmt.Sprintf("%.0f%%", *r.Score*100)
+				}
+
+				b.WriteString(fmt.Sprintf("%-17s %s   %5s ", r.Time.Format("2006-01-02 15:04"), show.StatusText(r.Status), score))
+				for i, id := range ids {
+					code := types.STATUS_CODE_UNKNOWN
+					if at := slices.IndexFunc(r.Checks, func(c types.RecordCheck) bool { return c.Rule.ID == id }); at != -1 {
+						code = r.Checks[at].Status
+					}
+					if i > 0 {
+						b.WriteString(" ")
+					}
+					b.WriteString(" " + fmt.Sprintf(show.SCHEMA.FmtForStatus(code), statusLetter(code)))
+				}
+				b.WriteString("\n")
+			}
+
+			if len(h.Failing) != 0 {
+				b.WriteString("\n")
+			}
+
+			for _, f := range h.Failing {
+				b.WriteString(fmt.Sprintf("%s %s: %s for %s since %s (%d checks)\n",
+					show.StatusText(f.Status), f.Rule.ID, f.Rule.About, duration(f.Until.Sub(f.Since)), f.Since.Format("2006-01-02 15:04"), f.Checks,
+				))
+			}
+
+			return b.Bytes(), nil
+		},
+	)
 )
+
+// ids of cluster rules that do not pass, warnings are superscript
+func clusterRules(checks []types.Status) string {
+	seq := make([]string, 0)
+	for _, status := range checks {
+		switch status.Code {
+		case types.STATUS_CODE_FAILURE:
+			seq = append(seq, fmt.Sprintf(show.SCHEMA.StatusCodeText.FAIL, status.Rule.ID))
+		case types.STATUS_CODE_WARNING:
+			seq = append(seq, fmt.Sprintf(show.SCHEMA.StatusCodeText.WARN, supsub.ToSup(status.Rule.ID)))
+		}
+	}
+
+	if len(seq) == 0 {
+		return ""
+	}
+
+	return " ¦ " + strings.Join(seq, " ")
+}
+
+func statusLetter(code types.StatusCode) string {
+	switch code {
+	case types.STATUS_CODE_SUCCESS:
+		return "P"
+	case types.STATUS_CODE_WARNING:
+		return "W"
+	case types.STATUS_CODE_FAILURE:
+		return "F"
+	default:
+		return "-"
+	}
+}
+
+// human readable duration in days, hours and minutes
+func duration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", d/(24*time.Hour), (d%(24*time.Hour))/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", d/time.Hour, (d%time.Hour)/time.Minute)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
@@ -0,0 +1,245 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// Show health status as SARIF 2.1.0 log. Rules are reporting descriptors
+// of the tool, each rule that warns or fails on the node is the result.
+// The node is the logical location of the result, the cluster is the location
+// of cluster rules.
+//
+
+type log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []run  `json:"runs"`
+}
+
+type run struct {
+	Tool    tool     `json:"tool"`
+	Results []result `json:"results"`
+}
+
+type tool struct {
+	Driver driver `json:"driver"`
+}
+
+type driver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []descriptor `json:"rules"`
+}
+
+type descriptor struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name,omitempty"`
+	ShortDescription message `json:"shortDescription"`
+}
+
+type message struct {
+	Text string `json:"text"`
+}
+
+type result struct {
+	RuleID     string         `json:"ruleId"`
+	RuleIndex  int            `json:"ruleIndex"`
+	Level      string         `json:"level"`
+	Message    message        `json:"message"`
+	Locations  []location     `json:"locations"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+type location struct {
+	LogicalLocations []logicalLocation `json:"logicalLocations"`
+}
+
+type logicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type builder struct {
+	rules   []descriptor
+	index   map[string]int
+	results []result
+}
+
+func newBuilder() *builder {
+	return &builder{
+		rules:   make([]descriptor, 0),
+		index:   make(map[string]int),
+		results: make([]result, 0),
+	}
+}
+
+func (b *builder) rule(r types.Rule) int {
+	id := r.ID
+	if id == "" {
+		id = r.About
+	}
+
+	if i, has := b.index[id]; has {
+		return i
+	}
+
+	b.index[id] = len(b.rules)
+	b.rules = append(b.rules, descriptor{ID: id, Name: r.About, ShortDescription: message{r.About}})
+	return b.index[id]
+}
+
+func (b *builder) node(cluster string, n types.StatusNode) {
+	fqn := n.Node.Name
+	if cluster != "" {
+		fqn = cluster + "/" + fqn
+	}
+
+	b.checks(n.Node.Name, fqn, n.Checks)
+}
+
+func (b *builder) checks(name, fqn string, checks []types.Status) {
+	for _, s := range checks {
+		i := b.rule(s.Rule)
+
+		level := ""
+		switch s.Code {
+		case types.STATUS_CODE_WARNING:
+			level = "warning"
+		case types.STATUS_CODE_FAILURE:
+			level = "error"
+		default:
+			continue
+		}
+
+		b.results = append(b.results, result{
+			RuleID:     b.rules[i].ID,
+			RuleIndex:  i,
+			Level:      level,
+			Message:    message{text(name, s)},
+			Locations:  []location{{[]logicalLocation{{Name: name, FullyQualifiedName: fqn, Kind: "resource"}}}},
+			Properties: properties(s),
+		})
+	}
+}
+
+func (b *builder) log() log {
+	return log{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []run{
+			{
+				Tool: tool{
+					Driver: driver{
+						Name:           "rds-health",
+						InformationURI: "https://github.com/zalando/rds-health",
+						Rules:          b.rules,
+					},
+				},
+				Results: b.results,
+			},
+		},
+	}
+}
+
+func text(node string, s types.Status) string {
+	text := fmt.Sprintf("%s %s %s", node, s.Code, s.Rule.About)
+
+	switch {
+	case s.SoftMM != nil:
+		text += fmt.Sprintf(": soft min/avg/max %.2f / %.2f / %.2f %s", s.SoftMM.Min, s.SoftMM.Avg, s.SoftMM.Max, s.Rule.Unit)
+	case s.Observed != nil:
+		text += ": " + *s.Observed
+	}
+
+	if s.Threshold != nil {
+		text += " (threshold " + s.Threshold.String() + ")"
+	}
+
+	return text
+}
+
+func properties(s types.Status) map[string]any {
+	props := map[string]any{}
+
+	if s.Rule.Unit != "" {
+		props["unit"] = s.Rule.Unit
+	}
+
+	if s.SoftMM != nil {
+		props["soft_min"] = s.SoftMM.Min
+		props["soft_avg"] = s.SoftMM.Avg
+		props["soft_max"] = s.SoftMM.Max
+	}
+
+	if s.Threshold != nil {
+		props["threshold"] = s.Threshold
+	}
+
+	if s.SuccessRate != nil {
+		props["success_rate"] = *s.SuccessRate
+	}
+
+	if s.Observed != nil {
+		props["observed"] = *s.Observed
+	}
+
+	return props
+}
+
+var (
+	showLog = show.FromShow[log](
+		func(l log) ([]byte, error) {
+			buf := &bytes.Buffer{}
+			enc := json.NewEncoder(buf)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(l); err != nil {
+				return nil, err
+			}
+
+			return buf.Bytes(), nil
+		},
+	)
+
+	// Show health status of the node as SARIF log
+	ShowHealthNode = show.ContraMap[log, types.StatusNode]{T: showLog}.FMap(
+		func(n types.StatusNode) log {
+			b := newBuilder()
+			b.node(n.Node.Cluster, n)
+			return b.log()
+		},
+	)
+
+	// Show health status of the region as SARIF log
+	ShowHealthRegion = show.ContraMap[log, types.StatusRegion]{T: showLog}.FMap(
+		func(r types.StatusRegion) log {
+			b := newBuilder()
+			for _, c := range r.Clusters {
+				for _, n := range slices.Concat(c.Writer, c.Reader) {
+					b.node(c.Cluster.ID, n)
+				}
+				b.checks(c.Cluster.ID, c.Cluster.ID, c.Checks)
+			}
+			for _, n := range r.Nodes {
+				b.node("", n)
+			}
+			return b.log()
+		},
+	)
+)
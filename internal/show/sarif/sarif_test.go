@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package sarif_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/zalando/rds-health/internal/show/sarif"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+type log struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Rules []struct {
+					ID string `json:"id"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			RuleIndex int    `json:"ruleIndex"`
+			Level     string `json:"level"`
+			Locations []struct {
+				LogicalLocations []struct {
+					Name               string `json:"name"`
+					FullyQualifiedName string `json:"fullyQualifiedName"`
+				} `json:"logicalLocations"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func region() types.StatusRegion {
+	rate, observed := 80.0, "eu-central-1a"
+
+	node := types.StatusNode{
+		Status: types.STATUS_CODE_FAILURE,
+		Node:   &types.Node{Name: "a", Type: "db.r6g.large"},
+		Checks: []types.Status{
+			{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1", Unit: "%", About: "cpu utilization"}},
+			{Code: types.STATUS_CODE_WARNING, Rule: types.Rule{ID: "C2", Unit: "%", About: "cpu i/o wait"}, SuccessRate: &rate, SoftMM: &types.MinMax{Min: 1, Avg: 6, Max: 20}},
+			{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "D3", Unit: "ms", About: "storage i/o latency"}, SoftMM: &types.MinMax{Min: 2, Avg: 12, Max: 40}},
+			{Code: types.STATUS_CODE_UNKNOWN, Rule: types.Rule{ID: "P1", About: "sql efficiency"}},
+		},
+	}
+
+	return types.StatusRegion{
+		Status: types.STATUS_CODE_FAILURE,
+		Clusters: []types.StatusCluster{
+			{
+				Status:  types.STATUS_CODE_FAILURE,
+				Cluster: &types.Cluster{ID: "c"},
+				Writer:  []types.StatusNode{node},
+				Checks: []types.Status{
+					{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "R4", About: "members in single zone"}, Observed: &observed},
+				},
+			},
+		},
+	}
+}
+
+func TestShowHealthRegion(t *testing.T) {
+	b, err := sarif.ShowHealthRegion.Show(region())
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	var l log
+	if err := json.Unmarshal(b, &l); err != nil {
+		t.Fatalf("should produce valid json %s", err)
+	}
+
+	if l.Version != "2.1.0" || len(l.Runs) != 1 {
+		t.Fatalf("unexpected log |%+v|", l)
+	}
+
+	run := l.Runs[0]
+	if len(run.Tool.Driver.Rules) != 5 {
+		t.Errorf("should describe all rules |%+v|", run.Tool.Driver.Rules)
+	}
+
+	// passed and unknown rules are not results
+	expect := []struct{ rule, level, fqn string }{
+		{"C2", "warning", "c/a"},
+		{"D3", "error", "c/a"},
+		{"R4", "error", "c"},
+	}
+
+	if len(run.Results) != len(expect) {
+		t.Fatalf("unexpected results |%+v|", run.Results)
+	}
+
+	for i, x := range run.Results {
+		loc := x.Locations[0].LogicalLocations[0]
+		switch {
+		case x.RuleID != expect[i].rule || x.Level != expect[i].level || loc.FullyQualifiedName != expect[i].fqn:
+			t.Errorf("unexpected result |%+v|", x)
+		case run.Tool.Driver.Rules[x.RuleIndex].ID != x.RuleID:
+			t.Errorf("unexpected index of rule |%+v|", x)
+		}
+	}
+}
+
+func TestShowHealthNode(t *testing.T) {
+	b, err := sarif.ShowHealthNode.Show(region().Clusters[0].Writer[0])
+	if err != nil {
+		t.Fatalf("should not fail with error %s", err)
+	}
+
+	var l log
+	if err := json.Unmarshal(b, &l); err != nil {
+		t.Fatalf("should produce valid json %s", err)
+	}
+
+	if len(l.Runs) != 1 || len(l.Runs[0].Results) != 2 || l.Runs[0].Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName != "a" {
+		t.Errorf("unexpected log |%+v|", l)
+	}
+}
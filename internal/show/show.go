@@ -12,8 +12,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/zalando/rds-health/internal/types"
+	"gopkg.in/yaml.v3"
 )
 
 //
@@ -155,6 +158,53 @@ func JSON[T any]() Printer[T] {
 	})
 }
 
+// outputs json as a single line, suitable for JSON Lines streams
+func JSONL[T any]() Printer[T] {
+	return FromShow[T](func(x T) ([]byte, error) {
+		b, err := json.Marshal(x)
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	})
+}
+
+// outputs yaml, the value is encoded using its json codec so that both
+// formats are consistent
+func YAML[T any]() Printer[T] {
+	return FromShow[T](func(x T) ([]byte, error) {
+		b, err := json.Marshal(x)
+		if err != nil {
+			return nil, err
+		}
+
+		// json is subset of yaml, decoding to node preserves order of keys
+		var node yaml.Node
+		if err := yaml.Unmarshal(b, &node); err != nil {
+			return nil, err
+		}
+		plainStyle(&node)
+
+		buf := &bytes.Buffer{}
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), enc.Close()
+	})
+}
+
+// drops json styles (flow collections and quoted keys), the encoder quotes
+// strings only if required
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		plainStyle(n)
+	}
+}
+
 // outputs nothing
 func None[T any]() Printer[T] {
 	return FromShow[T](func(x T) ([]byte, error) {
@@ -162,6 +212,28 @@ func None[T any]() Printer[T] {
 	})
 }
 
+// Registry of printers by the name of output format
+type Registry[T any] map[string]Printer[T]
+
+// Formats supported by the registry
+func (r Registry[T]) Formats() []string {
+	seq := make([]string, 0, len(r))
+	for format := range r {
+		seq = append(seq, format)
+	}
+	slices.Sort(seq)
+	return seq
+}
+
+// Lookup the printer of output format
+func (r Registry[T]) Lookup(format string) (Printer[T], error) {
+	if p, has := r[format]; has {
+		return p, nil
+	}
+
+	return nil, fmt.Errorf("output %s is not supported, use %s", format, strings.Join(r.Formats(), ", "))
+}
+
 type SchemaStatusCode struct {
 	NONE string
 	PASS string
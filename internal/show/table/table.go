@@ -0,0 +1,339 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package table
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// Show instances, health status and resource utilization as flat tables
+// (CSV, TSV or GitHub-flavored Markdown) suitable for spreadsheets and
+// documents. Clusters are flattened, each row has cluster and role columns.
+//
+
+// Format of table
+type Format struct {
+	Comma    rune // separator of values for CSV and TSV
+	Markdown bool
+}
+
+var (
+	CSV      = Format{Comma: ','}
+	TSV      = Format{Comma: '\t'}
+	Markdown = Format{Markdown: true}
+)
+
+var escapeMarkdown = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func (f Format) row(cells []string) ([]byte, error) {
+	if f.Markdown {
+		seq := make([]string, len(cells))
+		for i, c := range cells {
+			seq[i] = escapeMarkdown.Replace(c)
+		}
+		return []byte("| " + strings.Join(seq, " | ") + " |\n"), nil
+	}
+
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	w.Comma = f.Comma
+	if err := w.Write(cells); err != nil {
+		return nil, err
+	}
+	w.Flush()
+
+	return b.Bytes(), w.Error()
+}
+
+func (f Format) header(cells []string) string {
+	b, _ := f.row(cells)
+	if f.Markdown {
+		b = append(b, []byte("|"+strings.Repeat(" --- |", len(cells))+"\n")...)
+	}
+	return string(b)
+}
+
+// Table of rows produced from T, the header is omitted for empty tables
+func Table[T any](f Format, header []string, rows func(T) [][]string) show.Printer[T] {
+	return show.Prefix[T](f.header(header)).FMap(
+		show.ContraMap[[][]string, T]{T: show.Seq[[]string]{T: show.FromShow[[]string](f.row)}}.FMap(rows),
+	)
+}
+
+//
+// Flattening of clusters
+//
+
+// member of the region with its role (writer, reader or instance)
+type member[T any] struct {
+	Cluster string
+	Role    string
+	Node    T
+}
+
+func membersOf[C, T any](clusters []C, nodes []T, unapply func(C) (string, []T, []T)) []member[T] {
+	seq := make([]member[T], 0)
+	for _, c := range clusters {
+		id, writer, reader := unapply(c)
+		for _, n := range writer {
+			seq = append(seq, member[T]{id, "writer", n})
+		}
+		for _, n := range reader {
+			seq = append(seq, member[T]{id, "reader", n})
+		}
+	}
+
+	for _, n := range nodes {
+		seq = append(seq, member[T]{"", "instance", n})
+	}
+
+	return seq
+}
+
+func memberOf(node types.StatusNode) member[types.StatusNode] {
+	switch {
+	case node.Node.Cluster == "":
+		return member[types.StatusNode]{"", "instance", node}
+	case node.Node.ReadOnly:
+		return member[types.StatusNode]{node.Node.Cluster, "reader", node}
+	default:
+		return member[types.StatusNode]{node.Node.Cluster, "writer", node}
+	}
+}
+
+func num(x float64) string {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", x)
+}
+
+func engineOf(node *types.Node) (string, string) {
+	if node.Engine == nil {
+		return "", ""
+	}
+	return node.Engine.ID, node.Engine.Version
+}
+
+//
+// Config of instances
+//
+
+var configHeader = []string{"cluster", "role", "name", "engine", "version", "class", "cpu", "memory", "storage", "zones", "multi_az", "status"}
+
+func configRows(m member[types.Node]) []string {
+	engine, version := engineOf(&m.Node)
+
+	cpu, mem := "", ""
+	if m.Node.Compute != nil && m.Node.Compute.CPU != nil {
+		cpu = m.Node.Compute.CPU.String()
+	}
+	if m.Node.Compute != nil && m.Node.Compute.Memory != nil {
+		mem = m.Node.Compute.Memory.String()
+	}
+
+	storage := ""
+	if m.Node.Storage != nil {
+		storage = m.Node.Storage.String()
+	}
+
+	multiAZ, status := "", ""
+	if m.Node.Config != nil {
+		multiAZ = fmt.Sprintf("%t", m.Node.Config.MultiAZ)
+		status = m.Node.Config.Status
+	}
+
+	return []string{m.Cluster, m.Role, m.Node.Name, engine, version, m.Node.Type, cpu, mem, storage, m.Node.Zones.String(), multiAZ, status}
+}
+
+// Show instances of the region, one row per instance
+func ShowConfigRegion(f Format) show.Printer[types.Region] {
+	return Table(f, configHeader,
+		func(r types.Region) [][]string {
+			members := membersOf(r.Clusters, r.Nodes,
+				func(c types.Cluster) (string, []types.Node, []types.Node) { return c.ID, c.Writer, c.Reader },
+			)
+
+			rows := make([][]string, len(members))
+			for i, m := range members {
+				rows[i] = configRows(m)
+			}
+			return rows
+		},
+	)
+}
+
+//
+// Health status
+//
+
+var healthHeader = []string{"cluster", "role", "node", "engine", "class", "status", "id", "rule", "success_rate", "unit", "min", "avg", "max", "threshold"}
+
+func healthRows(m member[types.StatusNode]) [][]string {
+	engine, _ := engineOf(m.Node.Node)
+
+	rows := make([][]string, 0, len(m.Node.Checks))
+	for _, s := range m.Node.Checks {
+		rate, lo, avg, hi, threshold := "", "", "", "", ""
+		if s.SuccessRate != nil {
+			rate = num(*s.SuccessRate)
+		}
+		if s.SoftMM != nil {
+			lo, avg, hi = num(s.SoftMM.Min), num(s.SoftMM.Avg), num(s.SoftMM.Max)
+		}
+		if s.Threshold != nil {
+			threshold = s.Threshold.String()
+		}
+
+		rows = append(rows, []string{
+			m.Cluster, m.Role, m.Node.Node.Name, engine, m.Node.Node.Type,
+			strings.ToLower(s.Code.String()), s.Rule.ID, s.Rule.About, rate, s.Rule.Unit, lo, avg, hi, threshold,
+		})
+	}
+
+	return rows
+}
+
+// Show health status of the region, one row per node per rule and one row
+// per cluster rule
+func ShowHealthRegion(f Format) show.Printer[types.StatusRegion] {
+	return Table(f, healthHeader,
+		func(r types.StatusRegion) [][]string {
+			members := membersOf(r.Clusters, r.Nodes,
+				func(c types.StatusCluster) (string, []types.StatusNode, []types.StatusNode) {
+					return c.Cluster.ID, c.Writer, c.Reader
+				},
+			)
+
+			rows := make([][]string, 0)
+			for _, m := range members {
+				rows = append(rows, healthRows(m)...)
+			}
+
+			// cluster rules have the role "cluster"
+			for _, c := range r.Clusters {
+				cluster := types.StatusNode{Node: &types.Node{Name: c.Cluster.ID, Engine: c.Cluster.Engine}, Checks: c.Checks}
+				rows = append(rows, healthRows(member[types.StatusNode]{c.Cluster.ID, "cluster", cluster})...)
+			}
+			return rows
+		},
+	)
+}
+
+// Show health status of the node, one row per rule
+func ShowHealthNode(f Format) show.Printer[types.StatusNode] {
+	return Table(f, healthHeader,
+		func(n types.StatusNode) [][]string { return healthRows(memberOf(n)) },
+	)
+}
+
+//
+// Resource utilization
+//
+
+var valueHeader = []string{"cluster", "role", "node", "metric", "about", "unit", "soft_min", "soft_avg", "soft_max", "hard_min", "hard_avg", "hard_max"}
+
+func valueRows(m member[types.StatusNode]) [][]string {
+	rows := make([][]string, 0, len(m.Node.Checks))
+	for _, s := range m.Node.Checks {
+		metric := ""
+		if s.Series != nil {
+			metric = s.Series.Metric
+		}
+
+		row := []string{m.Cluster, m.Role, m.Node.Node.Name, metric, s.Rule.About, s.Rule.Unit}
+		for _, mm := range []*types.MinMax{s.SoftMM, s.HardMM} {
+			if mm == nil {
+				row = append(row, "", "", "")
+				continue
+			}
+			row = append(row, num(mm.Min), num(mm.Avg), num(mm.Max))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// Show resource utilization of the node, one row per metric
+func ShowValueNode(f Format) show.Printer[types.StatusNode] {
+	return Table(f, valueHeader,
+		func(n types.StatusNode) [][]string { return valueRows(memberOf(n)) },
+	)
+}
+
+// Show resource utilization of cluster members and cluster totals, one row
+// per member per metric, totals have the role "total"
+func ShowValueCluster(f Format) show.Printer[types.StatusCluster] {
+	return Table(f, valueHeader,
+		func(c types.StatusCluster) [][]string {
+			members := membersOf([]types.StatusCluster{c}, nil,
+				func(c types.StatusCluster) (string, []types.StatusNode, []types.StatusNode) {
+					return c.Cluster.ID, c.Writer, c.Reader
+				},
+			)
+
+			rows := make([][]string, 0)
+			for _, m := range members {
+				rows = append(rows, valueRows(m)...)
+			}
+
+			total := types.StatusNode{Node: &types.Node{Name: c.Cluster.ID}, Checks: c.Checks}
+			return append(rows, valueRows(member[types.StatusNode]{c.Cluster.ID, "total", total})...)
+		},
+	)
+}
+
+// Show soft average of metrics in the region, one row per node
+func ShowValueRegion(f Format) show.Printer[types.StatusRegion] {
+	return show.FromShow[types.StatusRegion](
+		func(r types.StatusRegion) ([]byte, error) {
+			members := membersOf(r.Clusters, r.Nodes,
+				func(c types.StatusCluster) (string, []types.StatusNode, []types.StatusNode) {
+					return c.Cluster.ID, c.Writer, c.Reader
+				},
+			)
+
+			// metrics are same for all nodes, the header is defined by the first one
+			header := []string{"cluster", "role", "node"}
+			if len(members) != 0 {
+				for _, s := range members[0].Node.Checks {
+					metric := s.Rule.About
+					if s.Series != nil {
+						metric = s.Series.Metric
+					}
+					header = append(header, metric)
+				}
+			}
+
+			rows := make([][]string, len(members))
+			for i, m := range members {
+				rows[i] = []string{m.Cluster, m.Role, m.Node.Node.Name}
+				for _, s := range m.Node.Checks {
+					avg := ""
+					if s.SoftMM != nil {
+						avg = num(s.SoftMM.Avg)
+					}
+					rows[i] = append(rows[i], avg)
+				}
+			}
+
+			return Table(f, header, func([][]string) [][]string { return rows }).Show(rows)
+		},
+	)
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package table_test
+
+import (
+	"testing"
+
+	"github.com/zalando/rds-health/internal/show/table"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestTable(t *testing.T) {
+	rows := func(seq [][]string) [][]string { return seq }
+
+	for name, tt := range map[string]struct {
+		format table.Format
+		rows   [][]string
+		expect string
+	}{
+		"csv empty": {
+			format: table.CSV,
+			rows:   nil,
+			expect: "",
+		},
+		"markdown empty": {
+			format: table.Markdown,
+			rows:   [][]string{},
+			expect: "",
+		},
+		"csv": {
+			format: table.CSV,
+			rows:   [][]string{{"x", "1"}},
+			expect: "a,b\nx,1\n",
+		},
+		"csv quoting": {
+			format: table.CSV,
+			rows:   [][]string{{"avg <= 40, max <= 60", `say "hi"`}},
+			expect: "a,b\n\"avg <= 40, max <= 60\",\"say \"\"hi\"\"\"\n",
+		},
+		"tsv": {
+			format: table.TSV,
+			rows:   [][]string{{"x, y", "1"}},
+			expect: "a\tb\nx, y\t1\n",
+		},
+		"markdown": {
+			format: table.Markdown,
+			rows:   [][]string{{"x", "1"}},
+			expect: "| a | b |\n| --- | --- |\n| x | 1 |\n",
+		},
+		"markdown escaping": {
+			format: table.Markdown,
+			rows:   [][]string{{"x|y", "multi\nline"}},
+			expect: "| a | b |\n| --- | --- |\n| x\\|y | multi line |\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := table.Table(tt.format, []string{"a", "b"}, rows).Show(tt.rows)
+			switch {
+			case err != nil:
+				t.Errorf("should not fail with error %s", err)
+			case string(b) != tt.expect:
+				t.Errorf("unexpected table |%q| != |%q|", b, tt.expect)
+			}
+		})
+	}
+}
+
+func TestShowHealthRegion(t *testing.T) {
+	region := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{
+				Cluster: &types.Cluster{ID: "c"},
+				Writer: []types.StatusNode{
+					{
+						Node: &types.Node{Name: "a", Type: "db.r6g.large", Cluster: "c"},
+						Checks: []types.Status{
+							{
+								Code:      types.STATUS_CODE_WARNING,
+								Rule:      types.Rule{ID: "C1", About: "cpu utilization", Unit: "%"},
+								SoftMM:    &types.MinMax{Min: 1, Avg: 2, Max: 3},
+								Threshold: &types.Threshold{Op: "below", Avg: 40, Peak: 60},
+							},
+						},
+					},
+				},
+				Checks: []types.Status{{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "R1", About: "writer"}}},
+			},
+		},
+	}
+
+	b, err := table.ShowHealthRegion(table.CSV).Show(region)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "cluster,role,node,engine,class,status,id,rule,success_rate,unit,min,avg,max,threshold\n" +
+		"c,writer,a,,db.r6g.large,warned,C1,cpu utilization,,%,1.00,2.00,3.00,\"avg <= 40, max <= 60\"\n" +
+		"c,cluster,c,,,passed,R1,writer,,,,,,\n"
+
+	if string(b) != expect {
+		t.Errorf("unexpected table |%s| != |%s|", b, expect)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/zalando/rds-health/internal/show"
+	"github.com/zalando/rds-health/internal/show/chart"
 	"github.com/zalando/rds-health/internal/show/minimal"
 	"github.com/zalando/rds-health/internal/types"
 )
@@ -23,6 +24,43 @@ import (
 //
 
 var (
+	// Show instance configuration, continues details about node
+	//		  Status ¦ available
+	//		Multi-AZ ¦ true
+	//		    IOPS ¦ 3000 iops, 125 MiB/s
+	showConfig = show.FromShow[types.Config](
+		func(c types.Config) ([]byte, error) {
+			provisioned := "-"
+			if c.IOPS != 0 || c.Throughput != 0 {
+				provisioned = fmt.Sprintf("%d iops, %d MiB/s", c.IOPS, c.Throughput)
+			}
+
+			autoscale := "-"
+			if c.MaxStorage != 0 {
+				autoscale = fmt.Sprintf("up to %s", c.MaxStorage)
+			}
+
+			insights := "disabled"
+			if c.Insights {
+				insights = fmt.Sprintf("%d days", c.InsightsRetention)
+			}
+
+			b := &bytes.Buffer{}
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Status", c.Status))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %t\n", "Multi-AZ", c.MultiAZ))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "IOPS", provisioned))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Autoscale", autoscale))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %t\n", "Encrypted", c.Encrypted))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Params", c.ParameterGroup))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %d days\n", "Backup", c.BackupRetention))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Insights", insights))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %t\n", "Deletion", c.DeletionProtection))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %t\n", "Public", c.PubliclyAccessible))
+			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "CA", c.CACertificate))
+			return b.Bytes(), nil
+		},
+	)
+
 	// Show detailed information about node:
 	//
 	//	example-database-a
@@ -32,12 +70,16 @@ var (
 	//		  Memory ¦ 8 GiB
 	//		 Storage ¦ 100 GiB, gp2
 	//       Zones ¦ eu-central-1b
+	//      Status ¦ available
+	//         ...
 	showConfigNode = show.FromShow[types.Node](
 		func(node types.Node) ([]byte, error) {
 			cpu := "-"
 			mem := "-"
-			if node.Compute != nil {
+			if node.Compute != nil && node.Compute.CPU != nil {
 				cpu = node.Compute.CPU.String()
+			}
+			if node.Compute != nil && node.Compute.Memory != nil {
 				mem = node.Compute.Memory.String()
 			}
 
@@ -52,8 +94,21 @@ var (
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Instance", node.Type))
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "CPU", cpu))
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Memory", mem))
+			if node.Compute != nil && node.Compute.Network != 0 {
+				b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Network", node.Compute.Network))
+			}
+			if node.Compute != nil && node.Compute.EBS != 0 {
+				b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "EBS", node.Compute.EBS))
+			}
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Storage", node.Storage))
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Zones", strings.Join(node.Zones, ", ")))
+			if node.Config != nil {
+				config, _ := showConfig.Show(*node.Config)
+				b.Write(config)
+			}
+			for _, m := range node.Maintenance {
+				b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Pending", m))
+			}
 			return b.Bytes(), nil
 		},
 	)
@@ -80,6 +135,9 @@ var (
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Engine", c.Engine))
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Writers", strings.Join(w, ", ")))
 			b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Readers", strings.Join(r, ", ")))
+			for _, m := range c.Maintenance {
+				b.WriteString(fmt.Sprintf("\t%9s ¦ %s\n", "Pending", m))
+			}
 			return b.Bytes(), nil
 		},
 	)
@@ -96,12 +154,35 @@ var (
 		showConfigNode,
 		func(sr types.Region) ([]types.Cluster, []types.Node) { return sr.Clusters, sr.Nodes },
 	)
+
+	// Show config of all instances in region, grouped by the tag
+	ShowConfigRegionGroups = show.Seq[types.RegionGroup]{
+		T: show.Printer2[types.RegionGroup, types.Group, types.Region]{
+			A: minimal.ShowGroup,
+			B: ShowConfigRegion,
+			UnApply2: func(g types.RegionGroup) (types.Group, types.Region) {
+				return g.Group, g.Region
+			},
+		},
+	}
 )
 
 //
 // Show health status about Nodes, Clusters, Regions
 //
 
+// width of sparklines
+const sparkWidth = 24
+
+// sparkline of avg values observed by the rule
+func sparkline(status types.Status) string {
+	if status.Series == nil {
+		return ""
+	}
+
+	return chart.Sparkline(status.Series.Avg, sparkWidth)
+}
+
 var (
 	// show MinMax measurement as one liner
 	showMinMax = show.FromShow[types.MinMax](
@@ -112,7 +193,7 @@ var (
 	)
 
 	// Show the status of single check
-	// FAILED   5.55%           0.56          11.53          44.80	 D3: storage i/o latency
+	// FAILED   5.55%           0.56          11.53          44.80  ▁▁▂▁▇█▂▁	 D3: storage i/o latency
 	showHealthRule = show.FromShow[types.Status](
 		func(status types.Status) ([]byte, error) {
 			b := &bytes.Buffer{}
@@ -123,7 +204,7 @@ var (
 			}
 
 			ffs := show.SCHEMA.FmtForStatus(status.Code)
-			b.WriteString(fmt.Sprintf(ffs+" "+ffs+" %4s %14.2f %14.2f %14.2f\t %s: %s\n", status.Code, fmt.Sprintf("%6.2f%%", rate), status.Rule.Unit, status.SoftMM.Min, status.SoftMM.Avg, status.SoftMM.Max, status.Rule.ID, status.Rule.About))
+			b.WriteString(fmt.Sprintf(ffs+" "+ffs+" %4s %14.2f %14.2f %14.2f  %-*s\t %s: %s\n", status.Code, fmt.Sprintf("%6.2f%%", rate), status.Rule.Unit, status.SoftMM.Min, status.SoftMM.Avg, status.SoftMM.Max, sparkWidth, sparkline(status), status.Rule.ID, status.Rule.About))
 			return b.Bytes(), nil
 		},
 	)
@@ -135,8 +216,10 @@ var (
 
 			cpu := "-"
 			mem := "-"
-			if node.Node.Compute != nil {
+			if node.Node.Compute != nil && node.Node.Compute.CPU != nil {
 				cpu = node.Node.Compute.CPU.String()
+			}
+			if node.Node.Compute != nil && node.Node.Compute.Memory != nil {
 				mem = node.Node.Compute.Memory.String()
 			}
 
@@ -172,7 +255,7 @@ var (
 	//			         % ¦ min: 17.5	avg: 25.0	max: 80.0
 	//
 	ShowHealthNode = show.Prefix[types.StatusNode](
-		fmt.Sprintf("%6s %7s %4s %14s %14s %14s\t%3s %s\n", "STATUS", "%", "UNIT", "MIN", "AVG", "MAX", "ID", "CHECK"),
+		fmt.Sprintf("%6s %7s %4s %14s %14s %14s  %-*s\t%3s %s\n", "STATUS", "%", "UNIT", "MIN", "AVG", "MAX", sparkWidth, "TREND", "ID", "CHECK"),
 	).FMap(
 		show.Printer2[types.StatusNode, []types.Status, types.StatusNode]{
 			A: show.Seq[types.Status]{T: showHealthRule},
@@ -184,6 +267,47 @@ var (
 	)
 )
 
+//
+// Show audit status about Nodes
+//
+
+var (
+	// Show the status of single audit rule
+	// PASSED A1: multi-az deployment              multi-az
+	showAuditRule = show.FromShow[types.Status](
+		func(status types.Status) ([]byte, error) {
+			observed := "-"
+			if status.Observed != nil {
+				observed = *status.Observed
+			}
+
+			ffs := show.SCHEMA.FmtForStatus(status.Code)
+			text := fmt.Sprintf(ffs+" %s: %-37s %s\n", status.Code, status.Rule.ID, status.Rule.About, observed)
+			return []byte(text), nil
+		},
+	)
+
+	// Show audit status of node and all rules
+	//	STATUS ID CHECK                                 OBSERVED
+	//	PASSED A1: multi-az deployment                  multi-az
+	//	WARNED A4: deletion protection                  disabled
+	//
+	//	WARN example-database-a
+	//		  Engine ¦ postgres v11.19
+	//		     ...
+	ShowAuditNode = show.Prefix[types.StatusNode](
+		fmt.Sprintf("%6s %-41s %s\n", "STATUS", "ID CHECK", "OBSERVED"),
+	).FMap(
+		show.Printer2[types.StatusNode, []types.Status, types.StatusNode]{
+			A: show.Seq[types.Status]{T: showAuditRule},
+			B: showHealthNodeWithSymbol,
+			UnApply2: func(sn types.StatusNode) ([]types.Status, types.StatusNode) {
+				return sn.Checks, sn
+			},
+		},
+	)
+)
+
 //
 // Show Values of Rules
 //
@@ -205,6 +329,10 @@ var (
 				b.WriteString(fmt.Sprintf("%s ¦ %s\n", "hard", string(hard)))
 			}
 
+			if status.Series != nil {
+				b.WriteString(fmt.Sprintf("%s ¦ %s\n", "plot", chart.Sparkline(status.Series.Avg, 2*sparkWidth)))
+			}
+
 			return b.Bytes(), nil
 		},
 	)
@@ -226,3 +354,104 @@ var (
 		},
 	}
 )
+
+// Show chart of the metric observed by the node, the metric is either
+// rule id (e.g. D3), metric name (e.g. os.cpuUtilization.total) or its
+// description (e.g. cpu utilization). The chart is fit into the width.
+func ShowChartNode(metric string, width int) show.Printer[types.StatusNode] {
+	showChart := show.FromShow[types.Status](
+		func(status types.Status) ([]byte, error) {
+			return []byte("\n" + chart.Plot(status, width, 16)), nil
+		},
+	)
+
+	return show.FromShow[types.StatusNode](
+		func(sn types.StatusNode) ([]byte, error) {
+			seq := make([]types.Status, 0)
+			known := make([]string, 0)
+			for _, s := range sn.Checks {
+				if s.Series == nil {
+					continue
+				}
+
+				if strings.EqualFold(metric, s.Rule.ID) || strings.EqualFold(metric, s.Rule.About) || metric == s.Series.Metric {
+					seq = append(seq, s)
+				}
+
+				known = append(known, s.Series.Metric)
+				if s.Rule.ID != "" {
+					known[len(known)-1] = s.Rule.ID
+				}
+			}
+
+			if len(seq) == 0 {
+				return nil, fmt.Errorf("metric %s is not observed, use one of %s", metric, strings.Join(known, ", "))
+			}
+
+			return show.Printer2[types.StatusNode, types.StatusNode, []types.Status]{
+				A:        showInfoNode,
+				B:        show.Seq[types.Status]{T: showChart},
+				UnApply2: func(sn types.StatusNode) (types.StatusNode, []types.Status) { return sn, seq },
+			}.Show(sn)
+		},
+	)
+}
+
+//
+// Show rightsize recommendations
+//
+
+var (
+	// Show sizing of the node using the class and reasoning per metric
+	//
+	// downsize db.r5.large (2 vcpu, mem 16 GiB)
+	//      cpu ¦  24.0% 0.48 of 2 vCPU busy, within target 60%
+	//     load ¦  40.0% 0.80 active sessions on 2 vCPU, within target 100%
+	//   memory ¦  12.5% 2.00 of 16 GiB used by processes, within target 50%
+	showSizing = show.FromShow[types.Sizing](
+		func(s types.Sizing) ([]byte, error) {
+			b := &bytes.Buffer{}
+
+			compute := ""
+			if s.Compute != nil {
+				compute = " (" + s.Compute.String() + ")"
+			}
+			b.WriteString(fmt.Sprintf("\n%s %s%s\n", s.Action, s.Class, compute))
+
+			for _, u := range s.Utilization {
+				value := fmt.Sprintf("%6s", "-")
+				if u.Observed {
+					value = fmt.Sprintf("%5.1f%%", u.Value)
+				}
+				b.WriteString(fmt.Sprintf("%8s ¦ %s %s\n", u.Metric, value, u.Reason))
+			}
+
+			return b.Bytes(), nil
+		},
+	)
+
+	// Show short information about node
+	showInfoRightsize = show.FromShow[types.Rightsize](
+		func(r types.Rightsize) ([]byte, error) {
+			text := fmt.Sprintf("%s (%s, %s)\n", r.Node.Name, r.Node.Type, r.Node.Engine)
+			if len(r.Options) == 0 {
+				text = fmt.Sprintf("%s (%s, %s): unknown instance class\n", r.Node.Name, r.Node.Type, r.Node.Engine)
+			}
+			return []byte(text), nil
+		},
+	)
+
+	// Show rightsize options of the node and reasoning
+	ShowRightsizeNode = show.Printer2[types.Rightsize, types.Rightsize, []types.Sizing]{
+		A: showInfoRightsize,
+		B: show.Seq[types.Sizing]{T: showSizing},
+		UnApply2: func(r types.Rightsize) (types.Rightsize, []types.Sizing) {
+			return r, r.Options
+		},
+	}
+
+	// Show rightsize options of all instances in region
+	ShowRightsizeRegion = show.Seq[types.Rightsize]{
+		T: show.Prefix[types.Rightsize]("\n").FMap(ShowRightsizeNode),
+	}
+)
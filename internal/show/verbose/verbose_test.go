@@ -0,0 +1,55 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package verbose_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/show/verbose"
+	"github.com/zalando/rds-health/internal/types"
+)
+
+func TestShowChartNode(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	node := types.StatusNode{
+		Node: &types.Node{Name: "a", Engine: &types.Engine{ID: "postgres", Version: "16.1"}},
+		Checks: []types.Status{
+			{
+				Rule:   types.Rule{ID: "C1", About: "cpu utilization", Unit: "%"},
+				Series: &types.Series{Metric: "os.cpuUtilization.total", Time: []time.Time{t0, t0.Add(time.Hour)}, Min: []float64{1, 2}, Avg: []float64{1, 2}, Max: []float64{1, 2}},
+			},
+			{Rule: types.Rule{ID: "P1", About: "sql efficiency"}},
+		},
+	}
+
+	for name, tt := range map[string]struct {
+		metric string
+		fails  bool
+	}{
+		"rule id":     {metric: "c1"},
+		"metric":      {metric: "os.cpuUtilization.total"},
+		"description": {metric: "CPU utilization"},
+		"no series":   {metric: "P1", fails: true},
+		"unknown":     {metric: "D3", fails: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := verbose.ShowChartNode(tt.metric, 60).Show(node)
+			switch {
+			case tt.fails && (err == nil || !strings.Contains(err.Error(), "use one of C1")):
+				t.Errorf("should fail with known metrics |%v|", err)
+			case !tt.fails && err != nil:
+				t.Errorf("should not fail with error %s", err)
+			case !tt.fails && !strings.Contains(string(b), "cpu utilization (%)"):
+				t.Errorf("should plot chart |%s|", b)
+			}
+		})
+	}
+}
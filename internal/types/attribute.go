@@ -0,0 +1,126 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//
+// Attributes of node, addressable by name for filtering and grouping
+//
+
+// Names of attributes supported by Node.Attribute
+var Attributes = []string{
+	"engine",
+	"version",
+	"class",
+	"zone",
+	"storage-type",
+	"status",
+	"multi-az",
+	"iops",
+	"throughput",
+	"max-storage",
+	"encrypted",
+	"parameter-group",
+	"backup-retention",
+	"insights",
+	"insights-retention",
+	"deletion-protection",
+	"public",
+	"ca-certificate",
+}
+
+// IsAttribute returns true if key is the name of node attribute
+func IsAttribute(key string) bool {
+	return slices.Contains(Attributes, key)
+}
+
+// Attribute returns the value of node attribute as string, numbers are
+// formatted as decimals, storage size in GiB, booleans as true or false.
+func (v Node) Attribute(key string) (string, bool) {
+	switch key {
+	case "engine":
+		if v.Engine != nil {
+			return v.Engine.ID, true
+		}
+		return "", true
+	case "version":
+		if v.Engine != nil {
+			return v.Engine.Version, true
+		}
+		return "", true
+	case "class":
+		return v.Type, true
+	case "zone":
+		return strings.Join(v.Zones, ","), true
+	case "storage-type":
+		if v.Storage != nil {
+			return v.Storage.Type, true
+		}
+		return "", true
+	}
+
+	if !IsAttribute(key) {
+		return "", false
+	}
+
+	if v.Config == nil {
+		return "", true
+	}
+
+	switch key {
+	case "status":
+		return v.Config.Status, true
+	case "multi-az":
+		return strconv.FormatBool(v.Config.MultiAZ), true
+	case "iops":
+		return strconv.Itoa(v.Config.IOPS), true
+	case "throughput":
+		return strconv.Itoa(v.Config.Throughput), true
+	case "max-storage":
+		return strconv.Itoa(int(v.Config.MaxStorage / GiB)), true
+	case "encrypted":
+		return strconv.FormatBool(v.Config.Encrypted), true
+	case "parameter-group":
+		return v.Config.ParameterGroup, true
+	case "backup-retention":
+		return strconv.Itoa(v.Config.BackupRetention), true
+	case "insights":
+		return strconv.FormatBool(v.Config.Insights), true
+	case "insights-retention":
+		return strconv.Itoa(v.Config.InsightsRetention), true
+	case "deletion-protection":
+		return strconv.FormatBool(v.Config.DeletionProtection), true
+	case "public":
+		return strconv.FormatBool(v.Config.PubliclyAccessible), true
+	case "ca-certificate":
+		return v.Config.CACertificate, true
+	default:
+		return "", false
+	}
+}
+
+// Label returns the value used to group the node by the key. The key is
+// either the name of attribute or the name of tag. Use prefix "tag:" to
+// explicitly refer to the tag that shares the name with an attribute.
+func (v Node) Label(key string) string {
+	if tag, has := strings.CutPrefix(key, "tag:"); has {
+		return v.Tags[tag]
+	}
+
+	if val, has := v.Attribute(key); has {
+		return val
+	}
+
+	return v.Tags[key]
+}
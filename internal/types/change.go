@@ -0,0 +1,40 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+// Kind of change between the baseline and the current health status
+type ChangeKind string
+
+const (
+	CHANGE_ADDED    ChangeKind = "added"    // node is not in the baseline
+	CHANGE_REMOVED  ChangeKind = "removed"  // node is only in the baseline
+	CHANGE_FAILURE  ChangeKind = "failure"  // rule status is degraded to warn or fail
+	CHANGE_RECOVERY ChangeKind = "recovery" // rule status is improved
+	CHANGE_CHANGED  ChangeKind = "changed"  // observed values are changed significantly
+)
+
+// Change of health status between the baseline and the current check. The
+// change of node has no rule, the change of rule has the rule and deltas of
+// its values.
+type Change struct {
+	Kind    ChangeKind `json:"kind"`
+	Node    string     `json:"node"`
+	Cluster string     `json:"cluster,omitempty"`
+	Rule    *Rule      `json:"rule,omitempty"`
+	From    StatusCode `json:"from"`
+	To      StatusCode `json:"to"`
+	Deltas  []Delta    `json:"deltas,omitempty"`
+}
+
+// Delta of the value observed by the rule
+type Delta struct {
+	Value string  `json:"value"` // success rate, min, avg or max
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+}
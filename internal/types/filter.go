@@ -0,0 +1,122 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Filter of database instances.
+//
+// Engines and Clusters are evaluated by AWS API (server-side), other
+// attributes are evaluated by Match (client-side).
+type Filter struct {
+	Tags       map[string]string // tags instance must have, empty value matches any
+	Attributes map[string]string // attributes instance must have (see Node.Attribute)
+	Engines    []string          // database engines (e.g. postgres, aurora-postgresql)
+	Classes    []string          // instance classes (e.g. db.r5.large)
+	Clusters   []string          // cluster identifiers
+	Names      []string          // glob patterns of instance name
+	Pattern    *regexp.Regexp    // regular expression of instance name
+}
+
+// IsEmpty returns true if filter accepts any instance
+func (f Filter) IsEmpty() bool {
+	return len(f.Tags) == 0 &&
+		len(f.Attributes) == 0 &&
+		len(f.Engines) == 0 &&
+		len(f.Classes) == 0 &&
+		len(f.Clusters) == 0 &&
+		len(f.Names) == 0 &&
+		f.Pattern == nil
+}
+
+// Match node against client-side filters
+func (f Filter) Match(node Node) bool {
+	for key, val := range f.Tags {
+		tag, has := node.Tags[key]
+		if !has || (val != "" && val != tag) {
+			return false
+		}
+	}
+
+	for key, val := range f.Attributes {
+		if attr, _ := node.Attribute(key); attr != val {
+			return false
+		}
+	}
+
+	if len(f.Classes) != 0 && !slices.Contains(f.Classes, node.Type) {
+		return false
+	}
+
+	if len(f.Names) != 0 && !slices.ContainsFunc(f.Names, func(glob string) bool {
+		ok, _ := path.Match(glob, node.Name)
+		return ok
+	}) {
+		return false
+	}
+
+	if f.Pattern != nil && !f.Pattern.MatchString(node.Name) {
+		return false
+	}
+
+	return true
+}
+
+// ParseTags decodes tag filters KEY=VALUE or KEY
+func ParseTags(seq []string) (map[string]string, error) {
+	if len(seq) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string, len(seq))
+	for _, tag := range seq {
+		key, val, _ := strings.Cut(tag, "=")
+		if key == "" {
+			return nil, fmt.Errorf("invalid tag filter %q, expected KEY=VALUE", tag)
+		}
+		tags[key] = val
+	}
+
+	return tags, nil
+}
+
+// ParseNames validates name glob patterns
+func ParseNames(seq []string) ([]string, error) {
+	for _, glob := range seq {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", glob, err)
+		}
+	}
+
+	return seq, nil
+}
+
+// ParseAttributes decodes attribute filters KEY=VALUE
+func ParseAttributes(seq []string) (map[string]string, error) {
+	if len(seq) == 0 {
+		return nil, nil
+	}
+
+	attrs := make(map[string]string, len(seq))
+	for _, attr := range seq {
+		key, val, has := strings.Cut(attr, "=")
+		if !has || !IsAttribute(key) {
+			return nil, fmt.Errorf("invalid attribute filter %q, expected KEY=VALUE, supported attributes: %s", attr, strings.Join(Attributes, ", "))
+		}
+		attrs[key] = val
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+//
+// Grouping of region objects by the value of tag or attribute
+//
+
+// Group identity, the tag (or attribute) and its value
+type Group struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Group of clusters and nodes sharing the same tag value
+type RegionGroup struct {
+	Group
+	Region
+}
+
+// Group of health statuses sharing the same tag value
+type StatusRegionGroup struct {
+	Group
+	StatusRegion
+}
+
+// value of the tag for the cluster, falls back to labels of cluster members
+func (c Cluster) groupOf(key string) string {
+	if !IsAttribute(key) {
+		if v, has := c.Tags[strings.TrimPrefix(key, "tag:")]; has {
+			return v
+		}
+	}
+
+	for _, n := range slices.Concat(c.Writer, c.Reader) {
+		if v := n.Label(key); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// GroupBy splits region into groups using the value of tag or attribute (see Node.Label)
+func (r Region) GroupBy(key string) []RegionGroup {
+	index := map[string]*RegionGroup{}
+	group := func(value string) *RegionGroup {
+		if g, has := index[value]; has {
+			return g
+		}
+
+		g := &RegionGroup{Group: Group{Key: key, Value: value}}
+		index[value] = g
+		return g
+	}
+
+	for _, c := range r.Clusters {
+		g := group(c.groupOf(key))
+		g.Clusters = append(g.Clusters, c)
+	}
+
+	for _, n := range r.Nodes {
+		g := group(n.Label(key))
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	seq := make([]RegionGroup, 0, len(index))
+	for _, g := range index {
+		seq = append(seq, *g)
+	}
+
+	sort.SliceStable(seq, func(i, j int) bool { return lessGroup(seq[i].Value, seq[j].Value) })
+	return seq
+}
+
+// GroupBy splits health status of region into groups using the value of tag or attribute,
+// the status of each group is the worst status of its members.
+func (r StatusRegion) GroupBy(key string) []StatusRegionGroup {
+	index := map[string]*StatusRegionGroup{}
+	group := func(value string) *StatusRegionGroup {
+		if g, has := index[value]; has {
+			return g
+		}
+
+		g := &StatusRegionGroup{Group: Group{Key: key, Value: value}}
+		index[value] = g
+		return g
+	}
+
+	for _, c := range r.Clusters {
+		g := group(c.Cluster.groupOf(key))
+		g.Clusters = append(g.Clusters, c)
+		if g.Status < c.Status {
+			g.Status = c.Status
+		}
+	}
+
+	for _, n := range r.Nodes {
+		g := group(n.Node.Label(key))
+		g.Nodes = append(g.Nodes, n)
+		if g.Status < n.Status {
+			g.Status = n.Status
+		}
+	}
+
+	seq := make([]StatusRegionGroup, 0, len(index))
+	for _, g := range index {
+		seq = append(seq, *g)
+	}
+
+	sort.SliceStable(seq, func(i, j int) bool { return lessGroup(seq[i].Value, seq[j].Value) })
+	return seq
+}
+
+// groups are ordered by value, the group of untagged objects is the last one
+func lessGroup(a, b string) bool {
+	switch {
+	case a == "":
+		return false
+	case b == "":
+		return true
+	default:
+		return a < b
+	}
+}
@@ -0,0 +1,54 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+import "time"
+
+// Kinds of records kept in the history
+const (
+	RECORD_NODE    = ""        // health rules of the instance
+	RECORD_CLUSTER = "cluster" // cluster rules, the node is the cluster
+)
+
+// Record of node health status kept in the history
+type Record struct {
+	Time    time.Time     `json:"time"`
+	Kind    string        `json:"kind,omitempty"`
+	Region  string        `json:"region,omitempty"`
+	Account string        `json:"account,omitempty"`
+	Node    string        `json:"node"`
+	Cluster string        `json:"cluster,omitempty"`
+	Status  StatusCode    `json:"status"`
+	Score   *float64      `json:"score,omitempty"` // share of passed rules (0..1)
+	Checks  []RecordCheck `json:"checks"`
+}
+
+// RecordCheck is status of the rule kept in the history
+type RecordCheck struct {
+	Rule        Rule       `json:"rule"`
+	Status      StatusCode `json:"status"`
+	SuccessRate *float64   `json:"success_rate,omitempty"`
+	Avg         *float64   `json:"avg,omitempty"` // soft avg
+}
+
+// Streak of consecutive records where the rule does not pass
+type Streak struct {
+	Rule   Rule       `json:"rule"`
+	Status StatusCode `json:"status"` // the latest status
+	Since  time.Time  `json:"since"`
+	Until  time.Time  `json:"until"`
+	Checks int        `json:"checks"`
+}
+
+// History of node health status, records are ordered by time
+type History struct {
+	Node    string   `json:"node"`
+	Records []Record `json:"records"`
+	Failing []Streak `json:"failing"`
+}
@@ -0,0 +1,58 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+// Sizing actions
+const (
+	SIZING_CURRENT  = "current"
+	SIZING_KEEP     = "keep"
+	SIZING_DOWNSIZE = "downsize"
+	SIZING_UPSIZE   = "upsize"
+	SIZING_CHANGE   = "change"
+)
+
+// Utilization of the compute resource by the workload
+type Utilization struct {
+	Metric   string  `json:"metric"`   // cpu, load or memory
+	Value    float64 `json:"value"`    // utilization, %
+	Target   float64 `json:"target"`   // target utilization, %
+	Reason   string  `json:"reason"`   // human readable explanation
+	Fit      bool    `json:"fit"`      // utilization is within the target
+	Observed bool    `json:"observed"` // metric is observed by the service
+}
+
+// Sizing of the node using the instance class
+type Sizing struct {
+	Class       string        `json:"class"`
+	Action      string        `json:"action"`
+	Compute     *Compute      `json:"compute,omitempty"`
+	Utilization []Utilization `json:"utilization"`
+}
+
+// Rightsize recommendations for the node, the first option is always
+// the current instance class.
+type Rightsize struct {
+	Node    *Node    `json:"node"`
+	Options []Sizing `json:"options"`
+}
+
+// Recommended sizing, the current one if there are no better options
+func (r Rightsize) Recommended() Sizing {
+	if len(r.Options) > 1 {
+		return r.Options[1]
+	}
+
+	if len(r.Options) == 1 {
+		current := r.Options[0]
+		current.Action = SIZING_KEEP
+		return current
+	}
+
+	return Sizing{Action: SIZING_KEEP}
+}
@@ -0,0 +1,29 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+import "time"
+
+// Point of raw time series collected by AWS Performance Insights
+type Point struct {
+	Time       time.Time `json:"timestamp"`
+	Metric     string    `json:"metric"`     // e.g. os.cpuUtilization.total
+	Aggregator string    `json:"aggregator"` // avg, min, max, sum or sample_count
+	Value      *float64  `json:"value"`      // nil if no data is collected
+}
+
+// Series of raw samples used by the rule, the min, avg and max values at
+// each moment of time. Missing values are NaN.
+type Series struct {
+	Metric string // e.g. os.cpuUtilization.total
+	Time   []time.Time
+	Min    []float64
+	Avg    []float64
+	Max    []float64
+}
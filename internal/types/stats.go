@@ -37,6 +37,25 @@ func (x Percentile) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (x *Percentile) UnmarshalJSON(b []byte) error {
+	var v map[string]any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	var err error
+	for _, f := range []struct {
+		key string
+		val *float64
+	}{{"p50", &x.P50}, {"p95", &x.P95}, {"p99", &x.P99}, {"p999", &x.P999}} {
+		if *f.val, err = decodeVal(v[f.key]); err != nil {
+			return fmt.Errorf("invalid %s: %w", f.key, err)
+		}
+	}
+
+	return nil
+}
+
 func NewPercentile(seq []float64) Percentile {
 	return Percentile{
 		P50:  maybeNaN(stats.Percentile(seq, 50.0)),
@@ -63,6 +82,25 @@ func (x MinMax) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (x *MinMax) UnmarshalJSON(b []byte) error {
+	var v map[string]any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	var err error
+	for _, f := range []struct {
+		key string
+		val *float64
+	}{{"min", &x.Min}, {"avg", &x.Avg}, {"max", &x.Max}} {
+		if *f.val, err = decodeVal(v[f.key]); err != nil {
+			return fmt.Errorf("invalid %s: %w", f.key, err)
+		}
+	}
+
+	return nil
+}
+
 func NewMinMax(min, avg, max []float64) MinMax {
 	return MinMax{
 		Min: maybeNaN(stats.Min(min)),
@@ -110,3 +148,18 @@ func encodeVal(x float64) any {
 
 	return x
 }
+
+func decodeVal(x any) (float64, error) {
+	switch v := x.(type) {
+	case float64:
+		return v, nil
+	case nil:
+		return math.NaN(), nil
+	case string:
+		if v == "NaN" {
+			return math.NaN(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("value %v is not a number", x)
+}
@@ -11,6 +11,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 )
@@ -90,9 +91,47 @@ func (code StatusCode) MarshalJSON() ([]byte, error) {
 	}
 }
 
+func (code *StatusCode) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	switch s {
+	case "unknown":
+		*code = STATUS_CODE_UNKNOWN
+	case "passed":
+		*code = STATUS_CODE_SUCCESS
+	case "warned":
+		*code = STATUS_CODE_WARNING
+	case "failed":
+		*code = STATUS_CODE_FAILURE
+	default:
+		return fmt.Errorf("status code %s unknown to JSON codec", s)
+	}
+
+	return nil
+}
+
 //
 //
 
+// Threshold of the rule. The rule warns if the average crosses the
+// threshold, it fails if the peak (max or min) crosses its threshold too.
+type Threshold struct {
+	Op   string  `json:"op"` // below or above
+	Avg  float64 `json:"avg"`
+	Peak float64 `json:"peak"` // threshold of max (below) or min (above)
+}
+
+func (t Threshold) String() string {
+	if t.Op == "above" {
+		return fmt.Sprintf("avg >= %g, min >= %g", t.Avg, t.Peak)
+	}
+
+	return fmt.Sprintf("avg <= %g, max <= %g", t.Avg, t.Peak)
+}
+
 // Status of rule evaluation
 type Status struct {
 	Code        StatusCode    `json:"status"`
@@ -103,6 +142,9 @@ type Status struct {
 	SoftMM      *MinMax       `json:"soft_minmax,omitempty"`
 	Aggregator  *string       `json:"aggregator,omitempty"`
 	Percentile  *Percentile   `json:"distribution,omitempty"`
+	Observed    *string       `json:"observed,omitempty"`
+	Threshold   *Threshold    `json:"threshold,omitempty"`
+	Series      *Series       `json:"-"`
 }
 
 func (v Status) String() string {
@@ -140,6 +182,24 @@ func (v Status) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (v *Status) UnmarshalJSON(b []byte) error {
+	type Struct Status
+
+	x := struct {
+		*Struct
+		IntervalInSec int `json:"interval"`
+	}{
+		Struct: (*Struct)(v),
+	}
+
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	v.Interval = time.Duration(x.IntervalInSec) * time.Second
+	return nil
+}
+
 //
 //
 
@@ -161,11 +221,67 @@ func (v StatusNode) String() string {
 	return sb.String()
 }
 
+// Score of node health, the share of passed rules (0..1). Rules with
+// unknown status are not scored.
+func (v StatusNode) Score() float64 {
+	passed, scored := 0, 0
+	for _, s := range v.Checks {
+		if s.Code == STATUS_CODE_UNKNOWN {
+			continue
+		}
+
+		scored++
+		if s.Code == STATUS_CODE_SUCCESS {
+			passed++
+		}
+	}
+
+	if scored == 0 {
+		return math.NaN()
+	}
+
+	return float64(passed) / float64(scored)
+}
+
+// WithRules keeps the status of matching rules only, the status code of
+// node is recomputed.
+func (v StatusNode) WithRules(match func(Rule) bool) StatusNode {
+	node := StatusNode{Status: STATUS_CODE_UNKNOWN, Node: v.Node, Checks: make([]Status, 0)}
+	for _, s := range v.Checks {
+		if match(s.Rule) {
+			node.Checks = append(node.Checks, s)
+			node.Status = max(node.Status, s.Code)
+		}
+	}
+
+	return node
+}
+
+// WithMetrics keeps the status of given metrics only in the given order,
+// the metric is identified by the name of its time series.
+func (v StatusNode) WithMetrics(metrics []string) StatusNode {
+	return StatusNode{Status: v.Status, Node: v.Node, Checks: withMetrics(v.Checks, metrics)}
+}
+
+func withMetrics(checks []Status, metrics []string) []Status {
+	out := make([]Status, 0, len(metrics))
+	for _, metric := range metrics {
+		for _, s := range checks {
+			if s.Series != nil && s.Series.Metric == metric {
+				out = append(out, s)
+			}
+		}
+	}
+
+	return out
+}
+
 type StatusCluster struct {
 	Status  StatusCode
 	Cluster *Cluster
 	Writer  []StatusNode
 	Reader  []StatusNode
+	Checks  []Status `json:",omitempty"` // rules evaluated across members
 }
 
 type StatusRegion struct {
@@ -174,6 +290,79 @@ type StatusRegion struct {
 	Nodes    []StatusNode
 }
 
+// WithRules keeps the status of matching rules only, the status codes of
+// region, clusters and nodes are recomputed. Cluster rules are filtered too.
+func (v StatusRegion) WithRules(match func(Rule) bool) StatusRegion {
+	nodes := func(seq []StatusNode) ([]StatusNode, StatusCode) {
+		code := STATUS_CODE_UNKNOWN
+		out := make([]StatusNode, len(seq))
+		for i, n := range seq {
+			out[i] = n.WithRules(match)
+			code = max(code, out[i].Status)
+		}
+		return out, code
+	}
+
+	region := StatusRegion{
+		Status:   STATUS_CODE_UNKNOWN,
+		Clusters: make([]StatusCluster, len(v.Clusters)),
+	}
+
+	for i, c := range v.Clusters {
+		writer, wcode := nodes(c.Writer)
+		reader, rcode := nodes(c.Reader)
+		region.Clusters[i] = StatusCluster{
+			Status:  max(wcode, rcode),
+			Cluster: c.Cluster,
+			Writer:  writer,
+			Reader:  reader,
+		}
+
+		for _, s := range c.Checks {
+			if match(s.Rule) {
+				region.Clusters[i].Checks = append(region.Clusters[i].Checks, s)
+				region.Clusters[i].Status = max(region.Clusters[i].Status, s.Code)
+			}
+		}
+		region.Status = max(region.Status, region.Clusters[i].Status)
+	}
+
+	var code StatusCode
+	region.Nodes, code = nodes(v.Nodes)
+	region.Status = max(region.Status, code)
+
+	return region
+}
+
+// WithMetrics keeps the status of given metrics only for each node and
+// cluster
+func (v StatusRegion) WithMetrics(metrics []string) StatusRegion {
+	nodes := func(seq []StatusNode) []StatusNode {
+		out := make([]StatusNode, len(seq))
+		for i, n := range seq {
+			out[i] = n.WithMetrics(metrics)
+		}
+		return out
+	}
+
+	region := StatusRegion{Status: v.Status, Clusters: make([]StatusCluster, len(v.Clusters))}
+	for i, c := range v.Clusters {
+		region.Clusters[i] = StatusCluster{
+			Status:  c.Status,
+			Cluster: c.Cluster,
+			Writer:  nodes(c.Writer),
+			Reader:  nodes(c.Reader),
+		}
+
+		if len(c.Checks) != 0 {
+			region.Clusters[i].Checks = withMetrics(c.Checks, metrics)
+		}
+	}
+	region.Nodes = nodes(v.Nodes)
+
+	return region
+}
+
 func (v StatusRegion) String() string {
 	formatter := func(prefix string, status StatusNode) string {
 		errors := make([]string, 0)
@@ -0,0 +1,29 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package types
+
+import "time"
+
+// Transition of health status between consecutive checks. The transition
+// of node status has no rule, the transition of rule status has the rule
+// and its latest status.
+type Transition struct {
+	Time    time.Time  `json:"time"`
+	Node    string     `json:"node"`
+	Cluster string     `json:"cluster,omitempty"`
+	Rule    *Rule      `json:"rule,omitempty"`
+	From    StatusCode `json:"from"`
+	To      StatusCode `json:"to"`
+	Status  *Status    `json:"status,omitempty"`
+}
+
+// IsRecovery returns true if status is improved
+func (t Transition) IsRecovery() bool {
+	return t.To == STATUS_CODE_SUCCESS && t.From > STATUS_CODE_SUCCESS
+}
@@ -10,13 +10,41 @@ package types
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 //
 // Common domain types used by the application
 //
 
+// ParseInterval decodes human-readable time interval either in minutes (m),
+// hours (h), days (d) or weeks (w), e.g. 7d
+func ParseInterval(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("time interval %q is not supported", interval)
+	}
+
+	v, err := strconv.Atoi(interval[0 : len(interval)-1])
+	if err != nil {
+		return 0, err
+	}
+
+	switch interval[len(interval)-1] {
+	case 'm':
+		return time.Duration(v) * time.Minute, nil
+	case 'h':
+		return time.Duration(v) * time.Hour, nil
+	case 'd':
+		return time.Duration(v) * time.Hour * 24, nil
+	case 'w':
+		return time.Duration(v) * time.Hour * 24 * 7, nil
+	default:
+		return 0, fmt.Errorf("time scale %s is not supported", interval)
+	}
+}
+
 // Binary Storage Unit
 type BiB uint
 
@@ -49,6 +77,16 @@ func (v GHz) String() string {
 	return fmt.Sprintf("%.2f GHz", v)
 }
 
+// Bandwidth data type, megabits per second
+type Mbps float64
+
+func (v Mbps) String() string {
+	if v >= 1000 {
+		return fmt.Sprintf("%g Gbps", float64(v)/1000)
+	}
+	return fmt.Sprintf("%g Mbps", float64(v))
+}
+
 // Storage specification
 type Storage struct {
 	Type string `json:"type"`
@@ -70,13 +108,18 @@ type CPU struct {
 }
 
 func (v CPU) String() string {
+	if v.Clock == 0 {
+		return fmt.Sprintf("%d vcpu", v.Cores)
+	}
 	return fmt.Sprintf("%d vcpu %s", v.Cores, v.Clock)
 }
 
 // Compute resource
 type Compute struct {
-	CPU    *CPU     `json:"cpu,omitempty"`
-	Memory *Storage `json:"memory,omitempty"`
+	CPU     *CPU     `json:"cpu,omitempty"`
+	Memory  *Storage `json:"memory,omitempty"`
+	Network Mbps     `json:"network,omitempty"` // peak network bandwidth
+	EBS     Mbps     `json:"ebs,omitempty"`     // maximum EBS bandwidth
 }
 
 func (v Compute) String() string {
@@ -89,6 +132,14 @@ func (v Compute) String() string {
 		spec = append(spec, v.Memory.String())
 	}
 
+	if v.Network != 0 {
+		spec = append(spec, fmt.Sprintf("net %s", v.Network))
+	}
+
+	if v.EBS != 0 {
+		spec = append(spec, fmt.Sprintf("ebs %s", v.EBS))
+	}
+
 	return strings.Join(spec, ", ")
 }
 
@@ -109,9 +160,54 @@ func (v Engine) String() string {
 	return fmt.Sprintf("%s v%s", v.ID, v.Version)
 }
 
+// Instance configuration
+type Config struct {
+	Status             string `json:"status"`
+	MultiAZ            bool   `json:"multi_az"`
+	IOPS               int    `json:"iops,omitempty"`        // provisioned storage iops
+	Throughput         int    `json:"throughput,omitempty"`  // provisioned storage throughput, MiB/s
+	MaxStorage         BiB    `json:"max_storage,omitempty"` // storage autoscaling limit
+	Encrypted          bool   `json:"encrypted"`
+	ParameterGroup     string `json:"parameter_group,omitempty"`
+	BackupRetention    int    `json:"backup_retention"` // days
+	Insights           bool   `json:"insights"`
+	InsightsRetention  int    `json:"insights_retention,omitempty"` // days
+	DeletionProtection bool   `json:"deletion_protection"`
+	PubliclyAccessible bool   `json:"publicly_accessible"`
+	CACertificate      string `json:"ca_certificate,omitempty"`
+}
+
+// Cluster configuration, members of Aurora cluster inherit these settings
+// from the cluster
+type ClusterConfig struct {
+	Encrypted          bool `json:"encrypted"`
+	BackupRetention    int  `json:"backup_retention"` // days
+	DeletionProtection bool `json:"deletion_protection"`
+}
+
+// Pending maintenance action
+type Maintenance struct {
+	Action      string     `json:"action"`
+	Description string     `json:"description,omitempty"`
+	AutoApply   *time.Time `json:"auto_applied_after,omitempty"`
+	ForcedApply *time.Time `json:"forced_apply_date,omitempty"`
+}
+
+func (v Maintenance) String() string {
+	switch {
+	case v.ForcedApply != nil:
+		return fmt.Sprintf("%s (forced on %s)", v.Action, v.ForcedApply.Format(time.DateOnly))
+	case v.AutoApply != nil:
+		return fmt.Sprintf("%s (auto after %s)", v.Action, v.AutoApply.Format(time.DateOnly))
+	default:
+		return v.Action
+	}
+}
+
 // Cluster Node
 type Node struct {
 	ID       string            `json:"id"`
+	ARN      string            `json:"arn,omitempty"`
 	Name     string            `json:"name"`
 	Type     string            `json:"type"`
 	Zones    AvailabilityZones `json:"zones"`
@@ -119,6 +215,11 @@ type Node struct {
 	Storage  *Storage          `json:"storage,omitempty"`
 	Compute  *Compute          `json:"compute,omitempty"`
 	ReadOnly bool              `json:"readonly"`
+	Cluster  string            `json:"cluster,omitempty"`
+	Config   *Config           `json:"config,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+
+	Maintenance []Maintenance `json:"maintenance,omitempty"`
 }
 
 func (v Node) String() string {
@@ -146,10 +247,15 @@ func (v Node) String() string {
 
 // DB cluster topology
 type Cluster struct {
-	ID     string  `json:"id"`
-	Engine *Engine `json:"engine,omitempty"`
-	Reader []Node  `json:"reader,omitempty"`
-	Writer []Node  `json:"writer,omitempty"`
+	ID     string            `json:"id"`
+	ARN    string            `json:"arn,omitempty"`
+	Engine *Engine           `json:"engine,omitempty"`
+	Reader []Node            `json:"reader,omitempty"`
+	Writer []Node            `json:"writer,omitempty"`
+	Config *ClusterConfig    `json:"config,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+
+	Maintenance []Maintenance `json:"maintenance,omitempty"`
 }
 
 // Region topology
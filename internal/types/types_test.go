@@ -9,8 +9,11 @@
 package types_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/zalando/rds-health/internal/types"
 )
@@ -54,6 +57,7 @@ func TestStorage(t *testing.T) {
 func TestCPU(t *testing.T) {
 	for value, expected := range map[types.CPU]string{
 		{4, types.GHz(2.2)}: "4 vcpu 2.20 GHz",
+		{2, types.GHz(0)}:   "2 vcpu",
 	} {
 		check(t, value, expected)
 	}
@@ -111,6 +115,208 @@ func TestNode(t *testing.T) {
 
 }
 
+func TestFilter(t *testing.T) {
+	node := types.Node{
+		Name: "payment-db-a",
+		Type: "db.r5.large",
+		Tags: map[string]string{"team": "payments"},
+		Config: &types.Config{
+			MultiAZ:         true,
+			BackupRetention: 7,
+		},
+	}
+
+	for filter, expected := range map[*types.Filter]bool{
+		{}:                                       true,
+		{Tags: map[string]string{"team": ""}}:    true,
+		{Tags: map[string]string{"team": "pay"}}: false,
+		{Tags: map[string]string{"owner": ""}}:   false,
+		{Classes: []string{"db.r5.large"}}:       true,
+		{Classes: []string{"db.t3.small"}}:       false,
+		{Names: []string{"payment-*"}}:           true,
+		{Names: []string{"search-*", "*-db-?"}}:  true,
+		{Names: []string{"search-*"}}:            false,
+		{Pattern: regexp.MustCompile(`-db-\w$`)}: true,
+		{Pattern: regexp.MustCompile(`^db-`)}:    false,
+		{Attributes: map[string]string{"multi-az": "true", "backup-retention": "7"}}: true,
+		{Attributes: map[string]string{"multi-az": "false"}}:                         false,
+	} {
+		if filter.Match(node) != expected {
+			t.Errorf("filter %+v should match %v", *filter, expected)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	node := types.Node{
+		Type:   "db.r5.large",
+		Config: &types.Config{MultiAZ: true},
+		Tags:   map[string]string{"team": "payments", "class": "gold"},
+	}
+
+	for key, expected := range map[string]string{
+		"team":      "payments",
+		"class":     "db.r5.large",
+		"tag:class": "gold",
+		"multi-az":  "true",
+		"owner":     "",
+	} {
+		if v := node.Label(key); v != expected {
+			t.Errorf("label %s = %s, expected %s", key, v, expected)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	pay := map[string]string{"team": "payments"}
+	search := map[string]string{"team": "search"}
+
+	region := types.StatusRegion{
+		Clusters: []types.StatusCluster{
+			{
+				Status:  types.STATUS_CODE_SUCCESS,
+				Cluster: &types.Cluster{ID: "a", Writer: []types.Node{{Name: "a-1", Tags: pay}}},
+			},
+		},
+		Nodes: []types.StatusNode{
+			{Status: types.STATUS_CODE_FAILURE, Node: &types.Node{Name: "b", Tags: pay}},
+			{Status: types.STATUS_CODE_WARNING, Node: &types.Node{Name: "c"}},
+			{Status: types.STATUS_CODE_SUCCESS, Node: &types.Node{Name: "d", Tags: search}},
+		},
+	}
+
+	seq := region.GroupBy("team")
+	switch {
+	case len(seq) != 3:
+		t.Errorf("should return 3 groups, got %d", len(seq))
+	case seq[0].Value != "payments" || seq[1].Value != "search" || seq[2].Value != "":
+		t.Errorf("should order groups by value, untagged last |%v|", seq)
+	case len(seq[0].Clusters) != 1 || len(seq[0].Nodes) != 1:
+		t.Errorf("should group cluster by tags of members")
+	case seq[0].Status != types.STATUS_CODE_FAILURE:
+		t.Errorf("should use worst status of members |%s|", seq[0].Status)
+	case seq[2].Status != types.STATUS_CODE_WARNING:
+		t.Errorf("should use worst status of members |%s|", seq[2].Status)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"30m": 30 * time.Minute,
+		"2h":  2 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"1w":  7 * 24 * time.Hour,
+	} {
+		if v, err := types.ParseInterval(value); err != nil || v != expected {
+			t.Errorf("unexpected interval %s |%s|", value, v)
+		}
+	}
+
+	for _, value := range []string{"", "d", "7x", "1.5h"} {
+		if _, err := types.ParseInterval(value); err == nil {
+			t.Errorf("should fail for %s", value)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tags, err := types.ParseTags([]string{"team=payments", "status"})
+	if err != nil || tags["team"] != "payments" || tags["status"] != "" || len(tags) != 2 {
+		t.Errorf("unexpected tags |%v| %v", tags, err)
+	}
+
+	if _, err := types.ParseTags([]string{"=payments"}); err == nil {
+		t.Errorf("should fail for empty key")
+	}
+
+	if _, err := types.ParseAttributes([]string{"unknown=1"}); err == nil {
+		t.Errorf("should fail for unknown attribute")
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	if _, err := types.ParseNames([]string{"payment-*", "db-?"}); err != nil {
+		t.Errorf("should not fail with error %s", err)
+	}
+
+	if _, err := types.ParseNames([]string{"payment-["}); err == nil {
+		t.Errorf("should fail on malformed pattern")
+	}
+}
+
+func TestStatusCodeJSON(t *testing.T) {
+	for _, code := range []types.StatusCode{types.STATUS_CODE_UNKNOWN, types.STATUS_CODE_SUCCESS, types.STATUS_CODE_WARNING, types.STATUS_CODE_FAILURE} {
+		b, err := json.Marshal(code)
+		if err != nil {
+			t.Fatalf("should not fail with error %s", err)
+		}
+
+		var v types.StatusCode
+		if err := json.Unmarshal(b, &v); err != nil || v != code {
+			t.Errorf("should decode %s |%s|", b, v)
+		}
+	}
+}
+
+func TestStatusWithRules(t *testing.T) {
+	node := types.StatusNode{
+		Status: types.STATUS_CODE_FAILURE,
+		Node:   &types.Node{Name: "a"},
+		Checks: []types.Status{
+			{Code: types.STATUS_CODE_SUCCESS, Rule: types.Rule{ID: "C1"}},
+			{Code: types.STATUS_CODE_WARNING, Rule: types.Rule{ID: "C2"}},
+			{Code: types.STATUS_CODE_FAILURE, Rule: types.Rule{ID: "D3"}},
+		},
+	}
+
+	region := types.StatusRegion{
+		Status: types.STATUS_CODE_FAILURE,
+		Clusters: []types.StatusCluster{
+			{Status: types.STATUS_CODE_FAILURE, Cluster: &types.Cluster{ID: "c"}, Writer: []types.StatusNode{node}},
+		},
+		Nodes: []types.StatusNode{node},
+	}
+
+	cpu := region.WithRules(func(r types.Rule) bool { return r.ID[0] == 'C' })
+	switch {
+	case cpu.Status != types.STATUS_CODE_WARNING:
+		t.Errorf("unexpected status of region |%s|", cpu.Status)
+	case cpu.Clusters[0].Status != types.STATUS_CODE_WARNING:
+		t.Errorf("unexpected status of cluster |%s|", cpu.Clusters[0].Status)
+	case len(cpu.Nodes[0].Checks) != 2 || len(region.Nodes[0].Checks) != 3:
+		t.Errorf("should filter checks without mutating the origin")
+	}
+
+	if score := node.Score(); score != 1.0/3.0 {
+		t.Errorf("unexpected score |%f|", score)
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	status := func(metric string) types.Status {
+		return types.Status{Rule: types.Rule{About: metric}, Series: &types.Series{Metric: metric}}
+	}
+
+	node := types.StatusNode{
+		Node:   &types.Node{Name: "a"},
+		Checks: []types.Status{status("os.memory.free"), status("db.load"), status("os.cpuUtilization.total")},
+	}
+
+	region := types.StatusRegion{
+		Clusters: []types.StatusCluster{{Cluster: &types.Cluster{ID: "c"}, Writer: []types.StatusNode{node}, Checks: node.Checks}},
+		Nodes:    []types.StatusNode{node},
+	}.WithMetrics([]string{"os.cpuUtilization.total", "os.memory.free"})
+
+	for _, checks := range [][]types.Status{region.Clusters[0].Writer[0].Checks, region.Nodes[0].Checks, region.Clusters[0].Checks} {
+		switch {
+		case len(checks) != 2:
+			t.Errorf("unexpected metrics |%+v|", checks)
+		case checks[0].Rule.About != "os.cpuUtilization.total" || checks[1].Rule.About != "os.memory.free":
+			t.Errorf("should keep order of metrics |%+v|", checks)
+		}
+	}
+}
+
 //
 // Helper
 //
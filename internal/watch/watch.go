@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package watch
+
+import (
+	"slices"
+	"time"
+
+	"github.com/zalando/rds-health/internal/types"
+)
+
+//
+// The package detects transitions of health status between consecutive
+// checks of the region. The first check is the baseline, only instances
+// and rules that do not pass are reported for it. Cluster rules are
+// observed as the node named after the cluster.
+//
+
+// Watcher keeps health status of the previous check
+type Watcher struct {
+	baseline bool
+	nodes    map[string]node
+	rules    map[string]types.StatusCode
+}
+
+type node struct {
+	name    string
+	cluster string
+	status  types.StatusCode
+}
+
+func New() *Watcher {
+	return &Watcher{
+		baseline: true,
+		nodes:    make(map[string]node),
+		rules:    make(map[string]types.StatusCode),
+	}
+}
+
+// Observe health status of the region, returns transitions since the
+// previous observation.
+func (w *Watcher) Observe(at time.Time, region types.StatusRegion) []types.Transition {
+	seq := make([]types.Transition, 0)
+	seen := make(map[string]bool)
+
+	// instances and clusters are kept under distinct keys, Aurora names
+	// the cluster and its instance alike
+	observe := func(id, cluster string, n types.StatusNode) {
+		seen[id] = true
+
+		prev, has := w.nodes[id]
+		if w.changed(has, prev.status, n.Status) {
+			seq = append(seq, types.Transition{Time: at, Node: n.Node.Name, Cluster: cluster, From: prev.status, To: n.Status})
+		}
+		w.nodes[id] = node{name: n.Node.Name, cluster: cluster, status: n.Status}
+
+		for _, s := range n.Checks {
+			key := id + "/" + s.Rule.ID + "/" + s.Rule.About
+			prev, has := w.rules[key]
+			if w.changed(has, prev, s.Code) {
+				rule, status := s.Rule, s
+				seq = append(seq, types.Transition{Time: at, Node: n.Node.Name, Cluster: cluster, Rule: &rule, From: prev, To: s.Code, Status: &status})
+			}
+			w.rules[key] = s.Code
+		}
+	}
+
+	for _, c := range region.Clusters {
+		for _, n := range slices.Concat(c.Writer, c.Reader) {
+			observe(n.Node.Name, c.Cluster.ID, n)
+		}
+		if len(c.Checks) != 0 {
+			observe("cluster:"+c.Cluster.ID, c.Cluster.ID, clusterOf(c))
+		}
+	}
+
+	for _, n := range region.Nodes {
+		observe(n.Node.Name, "", n)
+	}
+
+	// instances are removed or do not match filters anymore
+	ids := make([]string, 0)
+	for id := range w.nodes {
+		if !seen[id] {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		prev := w.nodes[id]
+		seq = append(seq, types.Transition{Time: at, Node: prev.name, Cluster: prev.cluster, From: prev.status, To: types.STATUS_CODE_UNKNOWN})
+		delete(w.nodes, id)
+		for key := range w.rules {
+			if len(key) > len(id) && key[:len(id)+1] == id+"/" {
+				delete(w.rules, key)
+			}
+		}
+	}
+
+	w.baseline = false
+	return seq
+}
+
+// status of cluster accounts cluster rules only, members are observed on
+// their own
+func clusterOf(c types.StatusCluster) types.StatusNode {
+	node := types.StatusNode{Status: types.STATUS_CODE_UNKNOWN, Node: &types.Node{Name: c.Cluster.ID}, Checks: c.Checks}
+	for _, s := range c.Checks {
+		node.Status = max(node.Status, s.Code)
+	}
+	return node
+}
+
+func (w *Watcher) changed(has bool, prev, next types.StatusCode) bool {
+	switch {
+	case w.baseline:
+		return next > types.STATUS_CODE_SUCCESS
+	case !has:
+		return next != types.STATUS_CODE_UNKNOWN
+	default:
+		return prev != next
+	}
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2024 Zalando SE
+//
+// This file may be modified and distributed under the terms
+// of the MIT license. See the LICENSE file for details.
+// https://github.com/zalando/rds-health
+//
+
+package watch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zalando/rds-health/internal/types"
+	"github.com/zalando/rds-health/internal/watch"
+)
+
+const (
+	pass = types.STATUS_CODE_SUCCESS
+	warn = types.STATUS_CODE_WARNING
+	fail = types.STATUS_CODE_FAILURE
+	none = types.STATUS_CODE_UNKNOWN
+)
+
+func region(nodes map[string][2]types.StatusCode) types.StatusRegion {
+	r := types.StatusRegion{}
+	for _, name := range []string{"a", "b", "c"} {
+		codes, has := nodes[name]
+		if !has {
+			continue
+		}
+
+		r.Nodes = append(r.Nodes, types.StatusNode{
+			Status: max(codes[0], codes[1]),
+			Node:   &types.Node{Name: name},
+			Checks: []types.Status{
+				{Code: codes[0], Rule: types.Rule{ID: "C1"}},
+				{Code: codes[1], Rule: types.Rule{ID: "D3"}},
+			},
+		})
+	}
+	return r
+}
+
+func expect(t *testing.T, seq []types.Transition, expected ...string) {
+	t.Helper()
+
+	if len(seq) != len(expected) {
+		t.Fatalf("unexpected transitions |%+v|", seq)
+	}
+
+	for i, x := range seq {
+		id := ""
+		if x.Rule != nil {
+			id = " " + x.Rule.ID
+		}
+
+		v := x.Node + id + " " + x.From.String() + " " + x.To.String()
+		if v != expected[i] {
+			t.Errorf("unexpected transition %s, expected %s", v, expected[i])
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	w := watch.New()
+	at := time.Now()
+
+	// baseline reports only instances and rules that do not pass
+	expect(t,
+		w.Observe(at, region(map[string][2]types.StatusCode{"a": {pass, pass}, "b": {pass, warn}})),
+		"b UNKNOWN WARNED",
+		"b D3 UNKNOWN WARNED",
+	)
+
+	// nothing is changed
+	expect(t,
+		w.Observe(at, region(map[string][2]types.StatusCode{"a": {pass, pass}, "b": {pass, warn}})),
+	)
+
+	// degradation and recovery
+	expect(t,
+		w.Observe(at, region(map[string][2]types.StatusCode{"a": {warn, pass}, "b": {pass, pass}})),
+		"a PASSED WARNED",
+		"a C1 PASSED WARNED",
+		"b WARNED PASSED",
+		"b D3 WARNED PASSED",
+	)
+
+	// new and removed instances
+	expect(t,
+		w.Observe(at, region(map[string][2]types.StatusCode{"a": {warn, pass}, "c": {pass, fail}})),
+		"c UNKNOWN FAILED",
+		"c C1 UNKNOWN PASSED",
+		"c D3 UNKNOWN FAILED",
+		"b PASSED UNKNOWN",
+	)
+}
+
+func TestWatchClusterRules(t *testing.T) {
+	w := watch.New()
+	at := time.Now()
+
+	// cluster and its writer are named alike
+	cluster := func(code types.StatusCode) types.StatusRegion {
+		return types.StatusRegion{
+			Clusters: []types.StatusCluster{
+				{
+					Cluster: &types.Cluster{ID: "a"},
+					Writer:  region(map[string][2]types.StatusCode{"a": {pass, pass}}).Nodes,
+					Checks:  []types.Status{{Code: code, Rule: types.Rule{ID: "R4"}}},
+				},
+			},
+		}
+	}
+
+	expect(t, w.Observe(at, cluster(pass)))
+
+	expect(t,
+		w.Observe(at, cluster(fail)),
+		"a PASSED FAILED",
+		"a R4 PASSED FAILED",
+	)
+
+	expect(t,
+		w.Observe(at, types.StatusRegion{}),
+		"a PASSED UNKNOWN",
+		"a FAILED UNKNOWN",
+	)
+}